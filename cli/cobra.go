@@ -22,12 +22,14 @@ import (
 func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *cobra.Command) {
 	opts := cliflags.NewClientOptions()
 	opts.InstallFlags(rootCmd.Flags())
+	opts.InstallPersistentFlags(rootCmd.PersistentFlags())
 
 	_ = rootCmd.MarkFlagDirname("config")
 	_ = rootCmd.RegisterFlagCompletionFunc(
 		"log-level",
 		completion.FromList("debug", "info", "warn", "error", "fatal"),
 	)
+	_ = rootCmd.RegisterFlagCompletionFunc("format", completion.FromList(cliflags.Formats...))
 
 	cobra.AddTemplateFunc("add", func(a, b int) int { return a + b })
 	cobra.AddTemplateFunc("hasAliases", hasAliases)
@@ -0,0 +1,455 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/install"
+	"go.wpm.so/cli/cli/command/why"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/advisory"
+	"go.wpm.so/cli/pkg/pm/releaseage"
+	"go.wpm.so/cli/pkg/pm/resolution"
+	"go.wpm.so/cli/pkg/pm/workspace"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+type auditOptions struct {
+	database   string
+	auditLevel string
+	fix        bool
+	dryRun     bool
+}
+
+func NewAuditCommand(wpmCli command.Cli) *cobra.Command {
+	var opts auditOptions
+
+	cmd := &cobra.Command{
+		Use:   "audit [OPTIONS]",
+		Short: "Check the locked packages for known vulnerabilities",
+		Args:  cli.NoArgs,
+		Example: `  wpm audit
+  wpm audit --audit-level high
+  wpm audit --db advisories.json
+  wpm audit --fix`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAudit(cmd.Context(), wpmCli, opts)
+		},
+	}
+
+	levels := make([]string, len(advisory.Severities))
+	for i, s := range advisory.Severities {
+		levels[i] = string(s)
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.database, "db", "", "Read advisories from this JSON file instead of the registry")
+	flags.StringVar(&opts.auditLevel, "audit-level", string(advisory.SeverityLow), `Lowest severity that fails the audit ("`+strings.Join(levels, `", "`)+`")`)
+	flags.BoolVar(&opts.fix, "fix", false, "Update vulnerable dependencies to patched versions")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "With --fix, do not write anything to disk")
+
+	_ = cmd.RegisterFlagCompletionFunc("audit-level", cobra.FixedCompletions(levels, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// finding is an advisory affecting a locked package.
+type finding struct {
+	advisory.Advisory
+	Version string     `json:"version"`
+	Paths   []why.Path `json:"paths"`
+}
+
+func runAudit(ctx context.Context, wpmCli command.Cli, opts auditOptions) error {
+	level := advisory.Severity(opts.auditLevel)
+	if !level.Valid() {
+		return fmt.Errorf("invalid audit level %q: must be low, moderate, high or critical", opts.auditLevel)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	// --fix installs, so it must not read wpm.json and wpm.lock while another
+	// wpm process is changing them.
+	if opts.fix {
+		contentDir := wpmjson.NewInDir(cwd).ContentDir()
+		if probe, _ := wpmjson.Read(cwd); probe != nil {
+			contentDir = probe.ContentDir()
+		}
+
+		wsLock, err := workspace.AcquireLock(ctx, filepath.Join(cwd, contentDir), func() {
+			wpmCli.Output().PrettyErrorln(output.Text{
+				Plain: "waiting for another wpm process to finish in this workspace...",
+				Fancy: aec.Faint.Apply("waiting for another wpm process to finish in this workspace..."),
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire workspace lock: %w", err)
+		}
+		defer func() {
+			_ = wsLock.Release()
+		}()
+	}
+
+	config, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return errors.New("no wpm.json found, so nothing to audit")
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	if lock == nil {
+		return errors.New("no wpm.lock found, run 'wpm install' first to generate a lockfile")
+	}
+
+	db, err := loadDatabase(ctx, wpmCli, opts.database, slices.Sorted(maps.Keys(lock.Packages)))
+	if err != nil {
+		return err
+	}
+	output.SanitizeValue(db)
+
+	findings, err := audit(config, lock, db)
+	if err != nil {
+		return err
+	}
+
+	switch command.OutputFormat(wpmCli) {
+	case cliflags.FormatJSON:
+		if findings == nil {
+			findings = []finding{}
+		}
+		if err := command.PrintJSON(wpmCli.Out(), findings); err != nil {
+			return err
+		}
+	case cliflags.FormatPlain:
+		printPlain(wpmCli.Out(), findings)
+	default:
+		if len(findings) == 0 {
+			wpmCli.Out().WriteString("No known vulnerabilities found.\n")
+			return nil
+		}
+		colorize := wpmCli.Out().IsColorEnabled()
+		printFindings(wpmCli.Out(), colorize, findings)
+		_, _ = fmt.Fprintf(wpmCli.Out(), "%s\n", summary(findings))
+	}
+
+	if opts.fix && len(findings) > 0 {
+		fixed, err := fix(ctx, wpmCli, cwd, config, db, findings, opts.dryRun)
+		if err != nil {
+			return err
+		}
+		if !opts.dryRun {
+			findings = slices.DeleteFunc(findings, func(f finding) bool { return fixed[f.Package] })
+		}
+	}
+
+	failing := 0
+	for _, f := range findings {
+		if f.Severity.Rank() >= level.Rank() {
+			failing++
+		}
+	}
+	if failing > 0 {
+		return cli.StatusError{
+			Status:     fmt.Sprintf("%s at or above %s severity", vulnerabilities(failing), level),
+			StatusCode: command.ExitCodeFindings,
+		}
+	}
+	return nil
+}
+
+func loadDatabase(ctx context.Context, wpmCli command.Cli, path string, packages []string) (*advisory.Database, error) {
+	if path != "" {
+		return advisory.ReadFile(path)
+	}
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var db *advisory.Database
+	err = wpmCli.Progress().RunWithProgress("", func() error {
+		db, err = client.GetAdvisories(ctx, packages)
+		return err
+	}, wpmCli.Err())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch advisories: %w", err)
+	}
+	if db == nil {
+		db = &advisory.Database{}
+	}
+	return db, nil
+}
+
+// audit matches every locked package against db. Findings are ordered by
+// severity, most serious first, then by package name.
+func audit(config *wpmjson.Config, lock *wpmlock.Lockfile, db *advisory.Database) ([]finding, error) {
+	var findings []finding
+	for _, name := range slices.Sorted(maps.Keys(lock.Packages)) {
+		version := lock.Packages[name].Version
+		matched, err := db.Match(name, version)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		paths := why.FindPaths(config, lock, name)
+		for _, a := range matched {
+			findings = append(findings, finding{Advisory: a, Version: version, Paths: paths})
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b finding) int {
+		return b.Severity.Rank() - a.Severity.Rank()
+	})
+	return findings, nil
+}
+
+// fix moves vulnerable direct dependencies to the lowest version that no
+// advisory affects, that runs on the project's runtime and that is old enough
+// for its minimum release age, then installs.
+// Vulnerable transitive dependencies are left to upgrading whatever requires
+// them. It returns the names of the packages it updated.
+func fix(ctx context.Context, wpmCli command.Cli, cwd string, config *wpmjson.Config, db *advisory.Database, findings []finding, dryRun bool) (map[string]bool, error) {
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var runtime *types.Runtime
+	if config.RuntimeStrict() {
+		runtime = config.Config.Runtime
+	}
+	gate, err := releaseage.New(config.MinimumReleaseAge())
+	if err != nil {
+		return nil, err
+	}
+
+	out := wpmCli.Out()
+	fixed := make(map[string]bool)
+	seen := make(map[string]bool)
+	_, _ = fmt.Fprintln(out)
+
+	for _, f := range findings {
+		if seen[f.Package] {
+			continue
+		}
+		seen[f.Package] = true
+
+		deps := directDependencies(config, f.Package)
+		if deps == nil {
+			_, _ = fmt.Fprintf(out, "%s can't be fixed directly; upgrade %s\n", f.Package, strings.Join(parents(f.Paths), " or "))
+			continue
+		}
+
+		packument, err := client.GetPackument(ctx, f.Package)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch versions of %s: %w", f.Package, err)
+		}
+		output.SanitizeValue(packument)
+
+		target := fixVersion(packument, f.Package, f.Version, db, runtime, gate)
+		if target == "" {
+			_, _ = fmt.Fprintf(out, "%s@%s has no patched version this project can install\n", f.Package, f.Version)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(out, "fixing %s@%s -> %s\n", f.Package, f.Version, target)
+		(*deps)[f.Package] = target
+		fixed[f.Package] = true
+	}
+
+	if len(fixed) == 0 {
+		return fixed, nil
+	}
+
+	err = install.Run(ctx, cwd, wpmCli, install.RunOptions{
+		DryRun:     dryRun,
+		Config:     config,
+		SaveConfig: !dryRun,
+		Trigger:    install.TriggerUpdate,
+	})
+	return fixed, err
+}
+
+// directDependencies returns the dependency list in wpm.json that names pkg,
+// or nil if it's only a transitive dependency.
+func directDependencies(config *wpmjson.Config, pkg string) *types.Dependencies {
+	for _, deps := range []*types.Dependencies{config.Dependencies, config.DevDependencies} {
+		if deps == nil {
+			continue
+		}
+		if _, ok := (*deps)[pkg]; ok {
+			return deps
+		}
+	}
+	return nil
+}
+
+// parents returns the direct dependencies that pull in a package, one per
+// path.
+func parents(paths []why.Path) []string {
+	var names []string
+	for _, p := range paths {
+		if len(p.Packages) > 0 && !slices.Contains(names, p.Packages[0].Name) {
+			names = append(names, p.Packages[0].Name)
+		}
+	}
+	return names
+}
+
+// fixVersion returns the lowest stable release of name newer than current
+// that no advisory in db affects, that runs on runtime and that gate lets
+// through, or "" if there is none.
+func fixVersion(p *manifest.Packument, name, current string, db *advisory.Database, runtime *types.Runtime, gate *releaseage.Gate) string {
+	cur, err := semver.NewVersion(current)
+	if err != nil {
+		return ""
+	}
+
+	var best *semver.Version
+	for v, pkg := range p.Versions {
+		sv, err := semver.NewVersion(v)
+		if err != nil || pkg == nil || sv.Prerelease() != "" || !sv.GreaterThan(cur) {
+			continue
+		}
+		if best != nil && !sv.LessThan(best) {
+			continue
+		}
+		if db.Affected(name, v) {
+			continue
+		}
+		if gate != nil && gate.Check(pkg) != nil {
+			continue
+		}
+		if resolution.CheckRuntime(pkg.Requires, runtime) != nil {
+			continue
+		}
+		best = sv
+	}
+
+	if best == nil {
+		return ""
+	}
+	return best.Original()
+}
+
+func printFindings(out io.Writer, colorize bool, findings []finding) {
+	c := func(a aec.ANSI, s string) string {
+		if !colorize {
+			return s
+		}
+		return a.Apply(s)
+	}
+
+	for _, f := range findings {
+		_, _ = fmt.Fprintf(out, "%s %s\n", c(severityColor(f.Severity), fmt.Sprintf("%-8s", f.Severity)), c(aec.Bold, f.Title))
+
+		row := func(label, value string) {
+			if label != "" {
+				label += ":"
+			}
+			_, _ = fmt.Fprintf(out, "         %-11s %s\n", label, value)
+		}
+		row("package", f.Package+"@"+f.Version)
+		row("vulnerable", f.Vulnerable)
+		if len(f.Patched) > 0 {
+			row("patched", strings.Join(f.Patched, ", "))
+		} else {
+			row("patched", c(aec.YellowF, "no fix available"))
+		}
+		id := f.ID
+		if len(f.CVEs) > 0 {
+			id += " (" + strings.Join(f.CVEs, ", ") + ")"
+		}
+		row("id", id)
+		if f.URL != "" {
+			row("more info", c(aec.CyanF, f.URL))
+		}
+		for i, p := range f.Paths {
+			if i == 0 {
+				row("path", formatPath(p))
+			} else {
+				row("", formatPath(p))
+			}
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+}
+
+func formatPath(p why.Path) string {
+	parts := []string{p.Root}
+	for _, pkg := range p.Packages {
+		parts = append(parts, pkg.Name)
+	}
+	return strings.Join(parts, " › ")
+}
+
+func severityColor(s advisory.Severity) aec.ANSI {
+	switch s {
+	case advisory.SeverityCritical, advisory.SeverityHigh:
+		return aec.RedF
+	case advisory.SeverityModerate:
+		return aec.YellowF
+	default:
+		return aec.DefaultF
+	}
+}
+
+// summary counts the findings by severity, e.g. "3 vulnerabilities found
+// (1 critical, 2 low)".
+func summary(findings []finding) string {
+	counts := make(map[advisory.Severity]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+
+	var parts []string
+	for i := len(advisory.Severities) - 1; i >= 0; i-- {
+		if n := counts[advisory.Severities[i]]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, advisory.Severities[i]))
+		}
+	}
+	return fmt.Sprintf("%s found (%s)", vulnerabilities(len(findings)), strings.Join(parts, ", "))
+}
+
+func vulnerabilities(n int) string {
+	if n == 1 {
+		return "1 vulnerability"
+	}
+	return fmt.Sprintf("%d vulnerabilities", n)
+}
+
+// printPlain prints one tab-separated line per finding: severity, package,
+// version, advisory ID, patched versions and title.
+func printPlain(out io.Writer, findings []finding) {
+	for _, f := range findings {
+		_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\t%s\n", f.Severity, f.Package, f.Version, f.ID, strings.Join(f.Patched, ","), f.Title)
+	}
+}
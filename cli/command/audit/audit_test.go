@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.wpm.so/cli/pkg/pm/advisory"
+	"go.wpm.so/cli/pkg/pm/releaseage"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+func TestFixVersion(t *testing.T) {
+	db := &advisory.Database{Advisories: []advisory.Advisory{
+		{ID: "A-1", Package: "akismet", Vulnerable: "<5.3.1"},
+		{ID: "A-2", Package: "akismet", Vulnerable: "5.3.1"},
+	}}
+	packument := &manifest.Packument{Versions: map[string]*manifest.Package{
+		"5.2.0":        {},
+		"5.3.0":        {},
+		"5.3.1":        {},
+		"5.3.2-beta.1": {},
+		"5.3.2":        {Requires: &types.Requires{PHP: ">=8.1"}},
+		"5.4.0":        {},
+	}}
+
+	if got := fixVersion(packument, "akismet", "5.3.0", db, nil, nil); got != "5.3.2" {
+		t.Errorf("fixVersion() = %q, want 5.3.2", got)
+	}
+
+	runtime := &types.Runtime{PHP: "7.4"}
+	if got := fixVersion(packument, "akismet", "5.3.0", db, runtime, nil); got != "5.4.0" {
+		t.Errorf("fixVersion() on PHP 7.4 = %q, want 5.4.0", got)
+	}
+
+	if got := fixVersion(packument, "akismet", "5.4.0", db, nil, nil); got != "" {
+		t.Errorf("fixVersion() past the latest = %q, want none", got)
+	}
+
+	gate, err := releaseage.New("72h", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	old := time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+	for v, pkg := range packument.Versions {
+		pkg.PublishedAt = old
+		if v == "5.3.2" {
+			pkg.PublishedAt = recent
+		}
+	}
+	if got := fixVersion(packument, "akismet", "5.3.0", db, nil, gate); got != "5.4.0" {
+		t.Errorf("fixVersion() with 5.3.2 too new = %q, want 5.4.0", got)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	config := &wpmjson.Config{Dependencies: &types.Dependencies{"akismet": "5.3.0"}}
+	lock := &wpmlock.Lockfile{Packages: map[string]wpmlock.LockPackage{
+		"akismet": {Version: "5.3.0", Dependencies: &types.Dependencies{"helper": "1.0.0"}},
+		"helper":  {Version: "1.0.0"},
+	}}
+	db := &advisory.Database{Advisories: []advisory.Advisory{
+		{ID: "A-1", Package: "akismet", Severity: advisory.SeverityLow, Vulnerable: "<5.3.1"},
+		{ID: "A-2", Package: "helper", Severity: advisory.SeverityCritical, Vulnerable: "<2"},
+	}}
+
+	findings, err := audit(config, lock, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	if want := []string{"A-2", "A-1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("findings = %v, want %v", ids, want)
+	}
+	if got, want := parents(findings[0].Paths), []string{"akismet"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parents of helper = %v, want %v", got, want)
+	}
+}
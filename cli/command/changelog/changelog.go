@@ -0,0 +1,186 @@
+package changelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+	"go.wpm.so/cli/pkg/wp/changelog"
+)
+
+type changelogOptions struct {
+	markdown bool
+}
+
+func NewChangelogCommand(wpmCli command.Cli) *cobra.Command {
+	var opts changelogOptions
+
+	cmd := &cobra.Command{
+		Use:   "changelog [OPTIONS] PACKAGE [FROM..TO]",
+		Short: "Show the changelog of a package between two versions",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		Example: `  wpm changelog akismet
+  wpm changelog akismet 5.3.1..5.4.0
+  wpm changelog akismet 5.4.0
+  wpm changelog --markdown akismet 5.0..`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var versions string
+			if len(args) == 2 {
+				versions = args[1]
+			}
+			return runChangelog(cmd.Context(), wpmCli, opts, args[0], versions)
+		},
+		ValidArgsFunction: completion.PackagesFromLockfile(),
+	}
+
+	cmd.Flags().BoolVar(&opts.markdown, "markdown", false, "Print the changelog as Markdown")
+
+	return cmd
+}
+
+func runChangelog(ctx context.Context, wpmCli command.Cli, opts changelogOptions, name, versions string) error {
+	from, to, single, err := parseRange(versions)
+	if err != nil {
+		return err
+	}
+
+	// Without a range, show what changed since the locked version.
+	if versions == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		if lock, err := wpmlock.Read(cwd); err == nil && lock != nil {
+			if pkg, ok := lock.Packages[name]; ok {
+				from = pkg.Version
+			}
+		}
+	}
+
+	target := to
+	if target == "" {
+		target = "latest"
+	}
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := client.GetPackageManifest(ctx, name, target, true)
+	if err != nil {
+		return fmt.Errorf("failed to fetch package %s@%s: %w", name, target, err)
+	}
+	if manifest.Readme == "" {
+		return fmt.Errorf("%s@%s has no readme to read a changelog from", name, manifest.Version)
+	}
+
+	releases, err := changelog.Between(manifest.Readme, from, manifest.Version)
+	if err != nil {
+		return err
+	}
+	output.SanitizeValue(&releases)
+	if single && len(releases) > 0 {
+		// Releases are newest first, so the requested one leads if present.
+		if releases[0].Version == manifest.Version {
+			releases = releases[:1]
+		} else {
+			releases = nil
+		}
+	}
+
+	if command.OutputFormat(wpmCli) == cliflags.FormatJSON {
+		if releases == nil {
+			releases = []changelog.Release{}
+		}
+		return command.PrintJSON(wpmCli.Out(), releases)
+	}
+
+	if len(releases) == 0 {
+		if from != "" {
+			_, _ = fmt.Fprintf(wpmCli.Out(), "No changelog entries for %s between %s and %s.\n", name, from, manifest.Version)
+		} else {
+			_, _ = fmt.Fprintf(wpmCli.Out(), "No changelog entries for %s up to %s.\n", name, manifest.Version)
+		}
+		return nil
+	}
+
+	if opts.markdown {
+		printMarkdown(wpmCli.Out(), name, releases)
+		return nil
+	}
+	colorize := wpmCli.Out().IsColorEnabled() && command.OutputFormat(wpmCli) != cliflags.FormatPlain
+	printText(wpmCli.Out(), colorize, releases)
+	return nil
+}
+
+// parseRange splits FROM..TO. Either side may be left out; a single version
+// selects that release only.
+func parseRange(s string) (from, to string, single bool, err error) {
+	if s == "" {
+		return "", "", false, nil
+	}
+	from, to, isRange := strings.Cut(s, "..")
+	if !isRange {
+		return "", s, true, nil
+	}
+	if from == "" && to == "" {
+		return "", "", false, errors.New(`invalid range "..": give at least one version`)
+	}
+	return from, to, false, nil
+}
+
+// printMarkdown writes releases as a Markdown document, with upgrade
+// notices as blockquotes.
+func printMarkdown(out io.Writer, name string, releases []changelog.Release) {
+	_, _ = fmt.Fprintf(out, "# %s changelog\n", name)
+	for _, r := range releases {
+		_, _ = fmt.Fprintf(out, "\n## %s\n", r.Version)
+		if r.UpgradeNotice != "" {
+			_, _ = fmt.Fprintf(out, "\n> **Upgrade notice:** %s\n", strings.ReplaceAll(r.UpgradeNotice, "\n", "\n> "))
+		}
+		if r.Notes != "" {
+			_, _ = fmt.Fprintf(out, "\n%s\n", r.Notes)
+		}
+	}
+}
+
+// printText writes releases for the terminal, with upgrade notices
+// highlighted.
+func printText(out io.Writer, colorize bool, releases []changelog.Release) {
+	c := func(a aec.ANSI, s string) string {
+		if !colorize {
+			return s
+		}
+		return a.Apply(s)
+	}
+
+	for i, r := range releases {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintln(out, c(aec.Bold, r.Version))
+		if r.UpgradeNotice != "" {
+			for line := range strings.SplitSeq(r.UpgradeNotice, "\n") {
+				_, _ = fmt.Fprintf(out, "  %s %s\n", c(aec.YellowF, "!"), c(aec.YellowF, line))
+			}
+		}
+		for line := range strings.SplitSeq(r.Notes, "\n") {
+			if strings.TrimSpace(line) != "" {
+				_, _ = fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package changelog
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		from, to string
+		single   bool
+		wantErr  bool
+	}{
+		{input: ""},
+		{input: "5.4.0", to: "5.4.0", single: true},
+		{input: "5.3.1..5.4.0", from: "5.3.1", to: "5.4.0"},
+		{input: "5.0..", from: "5.0"},
+		{input: "..5.4.0", to: "5.4.0"},
+		{input: "..", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		from, to, single, err := parseRange(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if from != tt.from || to != tt.to || single != tt.single {
+			t.Errorf("parseRange(%q) = %q, %q, %v, want %q, %q, %v", tt.input, from, to, single, tt.from, tt.to, tt.single)
+		}
+	}
+}
@@ -158,7 +158,7 @@ func (cli *WpmCli) RegistryClient() (registry.Client, error) {
 		token = os.Getenv("WPM_TOKEN")
 	}
 
-	return registry.New(
+	client, err := registry.New(
 		cli.Registry(),
 		token,
 		UserAgent(),
@@ -166,6 +166,15 @@ func (cli *WpmCli) RegistryClient() (registry.Client, error) {
 		cli.out.IsColorEnabled(),
 		cli.err,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	pinFile, err := config.Path("keys", registry.KeysPinFile(cli.Registry()))
+	if err != nil {
+		return nil, err
+	}
+	return registry.WithPinnedKeys(client, pinFile, cli.err), nil
 }
 
 // Output returns the output handler
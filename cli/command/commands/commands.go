@@ -4,14 +4,25 @@ import (
 	"github.com/spf13/cobra"
 
 	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/audit"
 	"go.wpm.so/cli/cli/command/auth"
+	"go.wpm.so/cli/cli/command/changelog"
+	"go.wpm.so/cli/cli/command/core"
 	"go.wpm.so/cli/cli/command/disttag"
+	"go.wpm.so/cli/cli/command/export"
+	"go.wpm.so/cli/cli/command/importer"
 	pmInit "go.wpm.so/cli/cli/command/init"
 	"go.wpm.so/cli/cli/command/install"
+	"go.wpm.so/cli/cli/command/licenses"
 	"go.wpm.so/cli/cli/command/ls"
 	"go.wpm.so/cli/cli/command/outdated"
 	"go.wpm.so/cli/cli/command/publish"
+	"go.wpm.so/cli/cli/command/sbom"
+	"go.wpm.so/cli/cli/command/search"
 	"go.wpm.so/cli/cli/command/uninstall"
+	"go.wpm.so/cli/cli/command/upgrade"
+	"go.wpm.so/cli/cli/command/version"
+	"go.wpm.so/cli/cli/command/view"
 	"go.wpm.so/cli/cli/command/whoami"
 	"go.wpm.so/cli/cli/command/why"
 )
@@ -21,12 +32,24 @@ func AddCommands(cmd *cobra.Command, wpmCli command.Cli) {
 		ls.NewLsCommand(wpmCli),
 		why.NewWhyCommand(wpmCli),
 		auth.NewAuthCommand(wpmCli),
+		core.NewCoreCommand(wpmCli),
 		pmInit.NewInitCommand(wpmCli),
 		whoami.NewWhoamiCommand(wpmCli),
 		disttag.NewDistTagCommand(wpmCli),
 		publish.NewPublishCommand(wpmCli),
+		publish.NewPackCommand(wpmCli),
+		importer.NewImportCommand(wpmCli),
+		export.NewExportCommand(wpmCli),
 		install.NewInstallCommand(wpmCli),
 		outdated.NewOutdatedCommand(wpmCli),
 		uninstall.NewUninstallCommand(wpmCli),
+		upgrade.NewUpgradeCommand(wpmCli),
+		changelog.NewChangelogCommand(wpmCli),
+		view.NewViewCommand(wpmCli),
+		search.NewSearchCommand(wpmCli),
+		version.NewVersionCommand(wpmCli),
+		sbom.NewSbomCommand(wpmCli),
+		audit.NewAuditCommand(wpmCli),
+		licenses.NewLicensesCommand(wpmCli),
 	)
 }
@@ -1,11 +1,15 @@
 package completion
 
 import (
+	"context"
 	"os"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"go.wpm.so/cli/pkg/pm/registry"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmlock"
@@ -64,6 +68,47 @@ func PackagesFromLockfile() cobra.CompletionFunc {
 	}
 }
 
+// registrySearchTimeout bounds how long completion waits on the registry, so
+// a slow or unreachable registry never stalls the shell.
+const registrySearchTimeout = 750 * time.Millisecond
+
+// PackagesFromRegistry offers completion for package names in the registry
+// that start with the word being completed. Nothing is offered for words
+// shorter than two characters or once a version is being typed.
+func PackagesFromRegistry(newClient func() (registry.Client, error)) cobra.CompletionFunc {
+	return Unique(func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(toComplete) < 2 || strings.Contains(toComplete, "@") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, registrySearchTimeout)
+		defer cancel()
+
+		result, err := client.Search(ctx, registry.SearchOptions{Text: toComplete, PerPage: 20})
+		if err != nil || result == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var names []string
+		for _, p := range result.Packages {
+			if strings.HasPrefix(p.Name, toComplete) {
+				names = append(names, p.Name)
+			}
+		}
+		sort.Strings(names)
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
 // PackageTypes offers completion for the closed set of valid package types.
 func PackageTypes() cobra.CompletionFunc {
 	return FromList(
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/version"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/workspace"
+	"go.wpm.so/cli/pkg/pm/wpcore"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	wpversion "go.wpm.so/cli/pkg/version"
+	"go.wpm.so/cli/pkg/wp/layout"
+	"go.wpm.so/cli/pkg/wp/parser"
+)
+
+func NewCoreCommand(wpmCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "core",
+		Short: "Manage WordPress core",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SetOut(wpmCli.Out())
+			cmd.HelpFunc()(cmd, args)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		newInstallCommand(wpmCli),
+		newUpdateCommand(wpmCli),
+		newVerifyCommand(wpmCli),
+	)
+
+	return cmd
+}
+
+// project is the wpm.json state every core subcommand starts from.
+type project struct {
+	cwd        string
+	cfg        *wpmjson.Config
+	version    string // normalized config.runtime.wp
+	coreDir    string // where core is installed, such as web/wp in Bedrock
+	contentDir string // the content directory, relative to coreDir
+}
+
+func printHeader(wpmCli command.Cli, name string) {
+	wpmCli.Output().Prettyln(output.Text{
+		Plain: "wpm core " + name + " v" + version.Version,
+		Fancy: aec.Bold.Apply("wpm core "+name) + " " + aec.LightBlackF.Apply("v"+version.Version),
+	})
+}
+
+func loadProject(cwd string) (*project, error) {
+	cfg, err := wpmjson.Read(cwd)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, errors.New("no wpm.json found, run 'wpm init' first")
+	}
+
+	runtimeWP := cfg.RuntimeWP()
+	if runtimeWP == "" {
+		return nil, errors.New("config.runtime.wp is not set in wpm.json, so there is no core version to manage")
+	}
+
+	v, err := wpversion.Normalize(runtimeWP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config.runtime.wp %q: %w", runtimeWP, err)
+	}
+
+	coreDir := filepath.Join(cwd, filepath.FromSlash(layout.Detect(cwd).Core()))
+	contentDir, err := filepath.Rel(coreDir, filepath.Join(cwd, cfg.ContentDir()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &project{cwd: cwd, cfg: cfg, version: v, coreDir: coreDir, contentDir: contentDir}, nil
+}
+
+// installedVersion returns the normalized version of the core install in
+// coreDir, or "" when core is not installed.
+func installedVersion(coreDir string) (string, error) {
+	v, err := parser.GetCoreVersion(coreDir)
+	if errors.Is(err, parser.ErrCoreNotInstalled) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	normalized, err := wpversion.Normalize(v)
+	if err != nil {
+		return "", fmt.Errorf("installed WordPress reports an invalid version %q: %w", v, err)
+	}
+	return normalized, nil
+}
+
+func acquireLock(ctx context.Context, wpmCli command.Cli, p *project) (*workspace.ProjectLock, error) {
+	lock, err := workspace.AcquireLock(ctx, filepath.Join(p.cwd, p.cfg.ContentDir()), func() {
+		wpmCli.Output().PrettyErrorln(output.Text{
+			Plain: "waiting for another wpm process to finish in this workspace...",
+			Fancy: aec.Faint.Apply("waiting for another wpm process to finish in this workspace..."),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire workspace lock: %w", err)
+	}
+	return lock, nil
+}
+
+func newSource(wpmCli command.Cli, p *project) (wpcore.Source, error) {
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	return wpcore.NewSource(p.cfg.CoreSource(), client, nil), nil
+}
+
+func getwd() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return cwd, nil
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpcore"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+func newInstallCommand(wpmCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the WordPress core version set in config.runtime.wp",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstall(cmd.Context(), wpmCli, false)
+		},
+	}
+
+	return cmd
+}
+
+// runInstall installs core at the version pinned by config.runtime.wp. When
+// update is false, an existing install of a different version is an error.
+func runInstall(ctx context.Context, wpmCli command.Cli, update bool) error {
+	cwd, err := getwd()
+	if err != nil {
+		return err
+	}
+
+	name := "install"
+	if update {
+		name = "update"
+	}
+	printHeader(wpmCli, name)
+
+	p, err := loadProject(cwd)
+	if err != nil {
+		return err
+	}
+
+	wsLock, err := acquireLock(ctx, wpmCli, p)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = wsLock.Release()
+	}()
+
+	installed, err := installedVersion(p.coreDir)
+	if err != nil {
+		return err
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	if lock == nil {
+		lock = wpmlock.New()
+	}
+	lock.SetIndentation(p.cfg.GetIndentation())
+
+	if installed != "" && installed != p.version && !update {
+		return fmt.Errorf("WordPress %s is already installed, run 'wpm core update' to switch to %s", installed, p.version)
+	}
+
+	wpmCli.Out().WriteString("\n")
+
+	if installed == p.version && lock.Core != nil && lock.Core.Version == p.version {
+		wpmCli.Out().WriteString("Already up-to-date!\n")
+		return nil
+	}
+
+	source, err := newSource(wpmCli, p)
+	if err != nil {
+		return err
+	}
+
+	var release *wpcore.Release
+	if err := wpmCli.Progress().RunWithProgress(
+		fmt.Sprintf("  Resolving WordPress %s", p.version),
+		func() error {
+			var err error
+			release, err = source.Resolve(ctx, p.version)
+			return err
+		},
+		wpmCli.Err(),
+	); err != nil {
+		return err
+	}
+
+	if err := wpmCli.Progress().RunWithProgress(
+		fmt.Sprintf("  Installing WordPress %s", p.version),
+		func() error {
+			rc, err := source.Open(ctx, release)
+			if err != nil {
+				return fmt.Errorf("failed to download WordPress %s: %w", release.Version, err)
+			}
+			defer func() { _ = rc.Close() }()
+
+			return wpcore.Install(ctx, p.coreDir, p.contentDir, rc, release.Digest)
+		},
+		wpmCli.Err(),
+	); err != nil {
+		return fmt.Errorf("failed to install WordPress %s: %w", release.Version, err)
+	}
+
+	lock.Core = &wpmlock.LockCore{
+		Version:    release.Version,
+		Source:     release.Source,
+		Digest:     release.Digest,
+		Signatures: release.Signatures,
+	}
+	if err := lock.Write(cwd); err != nil {
+		return fmt.Errorf("failed to save lockfile: %w", err)
+	}
+
+	color := aec.GreenF
+	if installed != "" {
+		color = aec.YellowF
+	}
+	wpmCli.Output().Prettyln(output.Text{
+		Plain: fmt.Sprintf("+ wordpress@%s", release.Version),
+		Fancy: fmt.Sprintf("%s %s %s", color.Apply("+"), aec.Bold.Apply("wordpress"), release.Version),
+	})
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package core
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+)
+
+func newUpdateCommand(wpmCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Switch WordPress core to the version set in config.runtime.wp",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstall(cmd.Context(), wpmCli, true)
+		},
+	}
+
+	return cmd
+}
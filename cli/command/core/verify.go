@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpcore"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+func newVerifyCommand(wpmCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check installed WordPress core against config.runtime.wp and its checksums",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVerify(cmd.Context(), wpmCli)
+		},
+	}
+
+	return cmd
+}
+
+func runVerify(ctx context.Context, wpmCli command.Cli) error {
+	cwd, err := getwd()
+	if err != nil {
+		return err
+	}
+
+	printHeader(wpmCli, "verify")
+
+	p, err := loadProject(cwd)
+	if err != nil {
+		return err
+	}
+
+	installed, err := installedVersion(p.coreDir)
+	if err != nil {
+		return err
+	}
+	if installed == "" {
+		return errors.New("WordPress core is not installed, run 'wpm core install' first")
+	}
+	if installed != p.version {
+		return fmt.Errorf("installed WordPress %s does not match config.runtime.wp %s, run 'wpm core update'", installed, p.version)
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	if lock == nil || lock.Core == nil {
+		return errors.New("WordPress core is not recorded in wpm.lock, run 'wpm core install' first")
+	}
+	if lock.Core.Version != p.version {
+		return fmt.Errorf("wpm.lock records WordPress %s but config.runtime.wp is %s, run 'wpm core update'", lock.Core.Version, p.version)
+	}
+
+	source, err := newSource(wpmCli, p)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []wpcore.Mismatch
+	if err := wpmCli.Progress().RunWithProgress(
+		fmt.Sprintf("  Verifying WordPress %s", p.version),
+		func() error {
+			release, err := source.Resolve(ctx, p.version)
+			if err != nil {
+				return err
+			}
+			if release.Digest != lock.Core.Digest {
+				return fmt.Errorf("digest for WordPress %s changed since it was locked: expected %s, got %s", p.version, lock.Core.Digest, release.Digest)
+			}
+
+			rc, err := source.Open(ctx, release)
+			if err != nil {
+				return fmt.Errorf("failed to download WordPress %s: %w", release.Version, err)
+			}
+			defer func() { _ = rc.Close() }()
+
+			mismatches, err = wpcore.Verify(ctx, p.coreDir, p.contentDir, rc, release.Digest)
+			return err
+		},
+		wpmCli.Err(),
+	); err != nil {
+		return err
+	}
+
+	wpmCli.Out().WriteString("\n")
+
+	if len(mismatches) == 0 {
+		wpmCli.Out().WriteString(fmt.Sprintf("WordPress %s is intact.\n", p.version))
+		return nil
+	}
+
+	for _, m := range mismatches {
+		wpmCli.Output().Prettyln(output.Text{
+			Plain: fmt.Sprintf("%s (%s)", m.Path, m.Reason),
+			Fancy: fmt.Sprintf("%s %s", m.Path, aec.RedF.Apply("("+m.Reason+")")),
+		})
+	}
+
+	return fmt.Errorf("%d core %s did not match WordPress %s", len(mismatches), command.Pluralize("file", "s", len(mismatches)), p.version)
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+	"go.wpm.so/cli/pkg/wp/wpcli"
+)
+
+const formatWPCLI = "wp-cli"
+
+type exportOptions struct {
+	format      string
+	packageType string
+	statusFrom  []string
+}
+
+func NewExportCommand(wpmCli command.Cli) *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print the locked packages in another tool's format",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(wpmCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	// This --format shadows the global one, which has no meaning for exports.
+	flags.StringVar(&opts.format, "format", "", "Output format (wp-cli)")
+	flags.StringVar(&opts.packageType, "type", "", "Only export packages of this type (plugin, theme)")
+	flags.StringSliceVar(&opts.statusFrom, "status-from", nil, "WP-CLI list to read activation status from")
+
+	_ = cmd.MarkFlagRequired("format")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{formatWPCLI}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("type", completion.PackageTypes())
+
+	return cmd
+}
+
+func runExport(wpmCli command.Cli, opts exportOptions) error {
+	if opts.format != formatWPCLI {
+		return fmt.Errorf("unsupported format %q, expected %s", opts.format, formatWPCLI)
+	}
+	pkgType := types.PackageType(opts.packageType)
+	if pkgType != "" {
+		if err := validator.IsValidPackageType(pkgType); err != nil {
+			return fmt.Errorf("type %w", err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return errors.New("no wpm.lock found, run 'wpm install' first")
+	}
+
+	statuses, err := readStatuses(opts.statusFrom)
+	if err != nil {
+		return err
+	}
+
+	return wpcli.Write(wpmCli.Out(), wpcliItems(lock, pkgType, statuses))
+}
+
+// wpcliItems lists the locked packages of pkgType, or of every type when
+// pkgType is empty, sorted by name.
+func wpcliItems(lock *wpmlock.Lockfile, pkgType types.PackageType, statuses map[string]string) []wpcli.Item {
+	items := make([]wpcli.Item, 0, len(lock.Packages))
+	for _, name := range slices.Sorted(maps.Keys(lock.Packages)) {
+		pkg := lock.Packages[name]
+		if pkgType != "" && pkg.Type != pkgType {
+			continue
+		}
+		items = append(items, wpcli.Item{
+			Name:    name,
+			Status:  statuses[name],
+			Version: pkg.Version,
+		})
+	}
+	return items
+}
+
+// readStatuses collects the status of each entry in the given WP-CLI lists.
+// wpm has no way to know activation on its own, so status is only exported
+// for packages one of these lists mentions.
+func readStatuses(files []string) (map[string]string, error) {
+	statuses := make(map[string]string)
+	for _, file := range files {
+		items, err := readList(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Status != "" {
+				statuses[item.Name] = item.Status
+			}
+		}
+	}
+	return statuses, nil
+}
+
+func readList(file string) ([]wpcli.Item, error) {
+	f, err := os.Open(file) //nolint:gosec // file is given by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	items, err := wpcli.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/jsonpretty"
+	"go.wpm.so/cli/pkg/streams"
+)
+
+// ExitCodeFindings is the exit status of a check such as wpm outdated, wpm
+// audit or wpm licenses when it finds something to report, so that CI can tell
+// findings apart from a failure, which exits with status 1.
+const ExitCodeFindings = 2
+
+// OutputFormat returns the output format selected with --format.
+func OutputFormat(wpmCli Cli) string {
+	if opts := wpmCli.Options(); opts != nil && opts.Format != "" {
+		return opts.Format
+	}
+	return cliflags.FormatTable
+}
+
+// PrintJSON writes v to out as indented JSON, colorized when out supports
+// color.
+func PrintJSON(out *streams.Out, v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return jsonpretty.Format(out, &buf, "  ", out.IsColorEnabled())
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/version"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/importer"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func NewImportCommand(wpmCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import dependencies from Composer or WP-CLI",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SetOut(wpmCli.Out())
+			cmd.HelpFunc()(cmd, args)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		newComposerCommand(wpmCli),
+		newWPCLICommand(wpmCli),
+	)
+
+	return cmd
+}
+
+// writeOptions are shared by every import subcommand.
+type writeOptions struct {
+	name   string
+	dryRun bool
+}
+
+func addWriteFlags(cmd *cobra.Command, opts *writeOptions) {
+	flags := cmd.Flags()
+	flags.StringVar(&opts.name, "name", "", "Package name when creating a new wpm.json")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Print what would be imported without writing wpm.json")
+}
+
+func printHeader(wpmCli command.Cli, name string) {
+	wpmCli.Output().Prettyln(output.Text{
+		Plain: "wpm import " + name + " v" + version.Version,
+		Fancy: aec.Bold.Apply("wpm import "+name) + " " + aec.LightBlackF.Apply("v"+version.Version),
+	})
+}
+
+// writeResult merges r into the wpm.json in cwd, creating a private project
+// config when there is none, and prints what was imported.
+func writeResult(wpmCli command.Cli, cwd string, r *importer.Result, opts writeOptions) error {
+	cfg, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		cfg = wpmjson.New()
+		cfg.Name = opts.name
+		if cfg.Name == "" {
+			cfg.Name = wpmjson.ProjectName(filepath.Base(cwd))
+		}
+		cfg.Private = true
+	}
+
+	r.Apply(cfg)
+
+	out := wpmCli.Output()
+	printDependencies(out, r.Dependencies, "")
+	printDependencies(out, r.DevDependencies, " (dev)")
+	printUnmapped(wpmCli, r.Unmapped)
+
+	count := len(r.Dependencies) + len(r.DevDependencies)
+	if opts.dryRun {
+		out.Prettyln(output.Text{
+			Plain: fmt.Sprintf("\n%d %s can be imported", count, command.Pluralize("package", "s", count)),
+			Fancy: fmt.Sprintf("\n%s %s can be imported", aec.GreenF.Apply(strconv.Itoa(count)), command.Pluralize("package", "s", count)),
+		})
+		return nil
+	}
+
+	if err := cfg.ValidateDependencyNames(); err != nil {
+		return err
+	}
+	if err := cfg.Write(cwd); err != nil {
+		return err
+	}
+
+	out.Prettyln(output.Text{
+		Plain: fmt.Sprintf("\n%d %s imported into %s, run 'wpm install' to install them", count, command.Pluralize("package", "s", count), wpmjson.ConfigFile),
+		Fancy: fmt.Sprintf("\n%s %s imported into %s, run %s to install them", aec.GreenF.Apply(strconv.Itoa(count)), command.Pluralize("package", "s", count), wpmjson.ConfigFile, aec.Bold.Apply("wpm install")),
+	})
+	return nil
+}
+
+func printDependencies(out *output.Output, deps types.Dependencies, suffix string) {
+	for _, name := range slices.Sorted(maps.Keys(deps)) {
+		out.Prettyln(output.Text{
+			Plain: fmt.Sprintf("+ %s@%s%s", name, deps[name], suffix),
+			Fancy: fmt.Sprintf("%s %s %s%s", aec.GreenF.Apply("+"), aec.Bold.Apply(name), deps[name], aec.Faint.Apply(suffix)),
+		})
+	}
+}
+
+func printUnmapped(wpmCli command.Cli, unmapped []importer.Unmapped) {
+	if len(unmapped) == 0 {
+		return
+	}
+
+	wpmCli.Output().PrettyErrorln(output.Text{
+		Plain: fmt.Sprintf("warn: %d %s could not be imported:", len(unmapped), command.Pluralize("item", "s", len(unmapped))),
+		Fancy: fmt.Sprintf("%s %d %s could not be imported:", aec.YellowF.Apply("warn:"), len(unmapped), command.Pluralize("item", "s", len(unmapped))),
+	})
+	for _, u := range unmapped {
+		wpmCli.Output().PrettyErrorln(output.Text{
+			Plain: fmt.Sprintf("  %s (%s)", u.Name, u.Reason),
+			Fancy: fmt.Sprintf("  %s %s", u.Name, aec.LightBlackF.Apply("("+u.Reason+")")),
+		})
+	}
+}
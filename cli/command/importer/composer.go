@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/pkg/pm/importer"
+)
+
+func newComposerCommand(wpmCli command.Cli) *cobra.Command {
+	var opts writeOptions
+
+	cmd := &cobra.Command{
+		Use:   "composer",
+		Short: "Import WPackagist dependencies from composer.json",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runComposer(cmd.Context(), wpmCli, opts)
+		},
+	}
+
+	addWriteFlags(cmd, &opts)
+
+	return cmd
+}
+
+func runComposer(ctx context.Context, wpmCli command.Cli, opts writeOptions) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	printHeader(wpmCli, "composer")
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return err
+	}
+	resolve := func(ctx context.Context, name string) ([]string, error) {
+		packument, err := client.GetPackument(ctx, name)
+		if err != nil || packument == nil {
+			return nil, err
+		}
+		return slices.Collect(maps.Keys(packument.Versions)), nil
+	}
+
+	var result *importer.Result
+	err = wpmCli.Progress().RunWithProgress("reading "+importer.ComposerFile, func() error {
+		var err error
+		result, err = importer.FromComposer(ctx, cwd, resolve)
+		return err
+	}, wpmCli.Err())
+	if err != nil {
+		return err
+	}
+
+	return writeResult(wpmCli, cwd, result, opts)
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
+	"go.wpm.so/cli/pkg/pm/importer"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+	"go.wpm.so/cli/pkg/wp/wpcli"
+)
+
+type wpcliOptions struct {
+	writeOptions
+	packageType string
+}
+
+func newWPCLICommand(wpmCli command.Cli) *cobra.Command {
+	var opts wpcliOptions
+
+	cmd := &cobra.Command{
+		Use:   "wp-cli FILE",
+		Short: "Import a WP-CLI plugin or theme list",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWPCLI(wpmCli, opts, args[0])
+		},
+	}
+
+	addWriteFlags(cmd, &opts.writeOptions)
+	cmd.Flags().StringVar(&opts.packageType, "type", string(types.TypePlugin), "Package type of the list (plugin, theme)")
+	_ = cmd.RegisterFlagCompletionFunc("type", completion.PackageTypes())
+
+	return cmd
+}
+
+func runWPCLI(wpmCli command.Cli, opts wpcliOptions, file string) error {
+	pkgType := types.PackageType(opts.packageType)
+	if err := validator.IsValidPackageType(pkgType); err != nil {
+		return fmt.Errorf("type %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	printHeader(wpmCli, "wp-cli")
+
+	items, err := readWPCLIList(wpmCli, file)
+	if err != nil {
+		return err
+	}
+
+	return writeResult(wpmCli, cwd, importer.FromWPCLI(items, pkgType), opts.writeOptions)
+}
+
+// readWPCLIList reads a WP-CLI list from file, or from stdin when file is "-".
+func readWPCLIList(wpmCli command.Cli, file string) ([]wpcli.Item, error) {
+	var r io.Reader = wpmCli.In()
+	if file != "-" {
+		f, err := os.Open(file) //nolint:gosec // file is given by the user
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file, err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	items, err := wpcli.Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return items, nil
+}
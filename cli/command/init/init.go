@@ -22,6 +22,7 @@ import (
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
 	"go.wpm.so/cli/pkg/version"
+	"go.wpm.so/cli/pkg/wp/layout"
 	"go.wpm.so/cli/pkg/wp/parser"
 )
 
@@ -163,12 +164,7 @@ func extractPackageHeaders(wpmCli command.Cli, cwd string, opts *initOptions) (m
 		return headers, headers.Version, nil
 
 	case "plugin":
-		dirEntries, dErr := os.ReadDir(cwd)
-		if dErr != nil {
-			return nil, "", fmt.Errorf("failed to read current directory for plugin files: %w", dErr)
-		}
-
-		foundPath, headers, fErr := findMainPluginFile(cwd, dirEntries)
+		foundPath, headers, fErr := parser.FindMainPluginFile(cwd)
 		if fErr != nil {
 			if opts.version == "" {
 				return nil, "", fmt.Errorf("failed to identify main plugin file: %w", fErr)
@@ -229,6 +225,10 @@ func runExistingInit(ctx context.Context, wpmCli command.Cli, opts *initOptions)
 		return nil
 	}
 
+	if opts.packageType == "" && isSiteRoot(cwd) {
+		return runSiteInit(ctx, wpmCli, opts, cwd)
+	}
+
 	if opts.packageType == "" {
 		opts.packageType = detectPackageType(cwd)
 		_, _ = fmt.Fprintf(wpmCli.Out(), "using package type: %s\n", opts.packageType)
@@ -261,6 +261,11 @@ func runExistingInit(ctx context.Context, wpmCli command.Cli, opts *initOptions)
 
 	wpmCfg := buildWpmConfig(ctx, *opts, opts.packageType, mainFileHeaders, readmeParser.GetMetadata(), resolveLatest)
 
+	if l := layout.Detect(cwd); l.Kind != layout.KindDefault {
+		applyLayout(wpmCfg, l)
+		_, _ = fmt.Fprintf(wpmCli.Out(), "detected %s layout, content directory: %s\n", l.Kind, l.ContentDir)
+	}
+
 	if err := resolveConfigVersion(wpmCli, wpmCfg, opts, extractedVersion); err != nil {
 		return err
 	}
@@ -446,26 +451,6 @@ func findExistingProjectFiles(cwd string) (existingProjectFiles, error) {
 	return paths, nil
 }
 
-func findMainPluginFile(cwd string, files []os.DirEntry) (string, parser.PluginFileHeaders, error) {
-	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(strings.ToLower(file.Name()), ".php") {
-			continue
-		}
-
-		filePath := filepath.Join(cwd, file.Name())
-		headers, err := parser.GetPluginHeaders(filePath)
-		if err != nil {
-			continue
-		}
-
-		if headers.Name != "" {
-			return filePath, headers, nil
-		}
-	}
-
-	return "", parser.PluginFileHeaders{}, errors.New("no main plugin file with valid plugin headers found")
-}
-
 func getMetaString(meta map[string]any, key, defaultValue string) string {
 	if val, ok := meta[key]; ok {
 		if strVal, ok := val.(string); ok && strVal != "" {
@@ -575,6 +560,16 @@ func buildWpmConfig(ctx context.Context, opts initOptions, pkgType string, mainF
 	return cfg
 }
 
+// applyLayout pins a detected, non-default directory layout in config so
+// later commands don't depend on detection.
+func applyLayout(cfg *wpmjson.Config, l *layout.Layout) {
+	if cfg.Config == nil {
+		cfg.Config = &types.PackageConfig{}
+	}
+	cfg.Config.ContentDir = l.ContentDir
+	cfg.Config.PluginDir = l.PluginDir
+}
+
 // removeCyclicDependency drops a dependency that points back to the package
 // itself.
 func removeCyclicDependency(cfg *wpmjson.Config) {
@@ -0,0 +1,333 @@
+package init
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/morikuni/aec"
+	"golang.org/x/sync/errgroup"
+
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/pkg/api"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+	"go.wpm.so/cli/pkg/version"
+	"go.wpm.so/cli/pkg/wp/layout"
+	"go.wpm.so/cli/pkg/wp/parser"
+)
+
+const siteLookupWorkers = 16
+
+// siteEntry is a plugin or theme found while scanning a site.
+type siteEntry struct {
+	Type    types.PackageType
+	Name    string // directory name, used as the registry slug
+	Path    string // project-relative, slash-separated
+	Version string // normalized installed version
+	Reason  string // why wpm can't manage the entry; empty if it can
+}
+
+// packageLookup fetches the manifest of name at version from the registry.
+type packageLookup func(ctx context.Context, name, version string) (*manifest.Package, error)
+
+// isSiteRoot reports whether dir holds a WordPress site rather than a single
+// plugin or theme.
+func isSiteRoot(dir string) bool {
+	for _, p := range []string{"wp-config.php", filepath.Join("wp-includes", "version.php")} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err == nil {
+			return true
+		}
+	}
+	return layout.Detect(dir).Kind == layout.KindBedrock
+}
+
+// runSiteInit writes a private wpm.json that pins every plugin and theme of
+// the site in cwd that the registry has at the installed version, and lists
+// the ones it has to leave alone.
+func runSiteInit(ctx context.Context, wpmCli command.Cli, opts *initOptions, cwd string) error {
+	l := layout.Detect(cwd)
+	_, _ = fmt.Fprintf(wpmCli.Out(), "WordPress site detected (%s layout), content directory: %s\n", l.Kind, l.ContentDir)
+
+	entries, err := scanSite(cwd, l)
+	if err != nil {
+		return err
+	}
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return err
+	}
+	lookup := func(ctx context.Context, name, version string) (*manifest.Package, error) {
+		return client.GetPackageManifest(ctx, name, version, false)
+	}
+
+	label := fmt.Sprintf("resolving %d packages", len(entries))
+	if err := wpmCli.Progress().RunWithProgress(label, func() error {
+		return matchSite(ctx, entries, lookup)
+	}, wpmCli.Err()); err != nil {
+		return err
+	}
+
+	name := opts.name
+	if name == "" {
+		name = wpmjson.ProjectName(filepath.Base(cwd))
+	}
+	if err := validator.IsValidPackageName(name); err != nil {
+		return fmt.Errorf("name %w", err)
+	}
+
+	coreVersion, err := parser.GetCoreVersion(filepath.Join(cwd, filepath.FromSlash(l.Core())))
+	if err != nil && !errors.Is(err, parser.ErrCoreNotInstalled) {
+		return err
+	}
+	if coreVersion != "" {
+		if coreVersion, err = version.Normalize(coreVersion); err != nil {
+			coreVersion = ""
+		}
+	}
+
+	cfg := buildSiteConfig(name, l, coreVersion, entries)
+	if err := cfg.Write(cwd); err != nil {
+		return err
+	}
+
+	printSiteSummary(wpmCli, cfg, entries)
+	_, _ = fmt.Fprintf(wpmCli.Out(), "%s created at %s\n", wpmjson.ConfigFile, filepath.Join(cwd, wpmjson.ConfigFile))
+
+	return nil
+}
+
+// scanSite lists the plugins, must-use plugins and themes installed under
+// the layout's directories. Entries that can't be managed carry a Reason.
+func scanSite(root string, l *layout.Layout) ([]siteEntry, error) {
+	plugins, err := scanPackages(root, l.Plugins(), types.TypePlugin)
+	if err != nil {
+		return nil, err
+	}
+	themes, err := scanPackages(root, l.Themes(), types.TypeTheme)
+	if err != nil {
+		return nil, err
+	}
+	muPlugins, err := scanMuPlugins(root, l.MuPlugins())
+	if err != nil {
+		return nil, err
+	}
+
+	// Dependencies are keyed by name alone, so a theme can't share a slug
+	// with a plugin.
+	pluginNames := make(map[string]bool, len(plugins))
+	for _, e := range plugins {
+		pluginNames[e.Name] = true
+	}
+	for i := range themes {
+		if themes[i].Reason == "" && pluginNames[themes[i].Name] {
+			themes[i].Reason = "a plugin with the same name is installed"
+		}
+	}
+
+	return slices.Concat(plugins, themes, muPlugins), nil
+}
+
+// scanPackages reads the headers of each plugin or theme directly under
+// root/dir and normalizes its version.
+func scanPackages(root, dir string, pkgType types.PackageType) ([]siteEntry, error) {
+	dirEntries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var entries []siteEntry
+	for _, de := range dirEntries {
+		name := de.Name()
+		if strings.HasPrefix(name, ".") || name == "index.php" {
+			continue
+		}
+
+		e := siteEntry{Type: pkgType, Name: name, Path: path.Join(dir, name)}
+		if !de.IsDir() {
+			if pkgType == types.TypePlugin && strings.EqualFold(filepath.Ext(name), ".php") {
+				e.Reason = "single-file plugin"
+				entries = append(entries, e)
+			}
+			continue
+		}
+
+		rawVersion, ok := readPackageVersion(filepath.Join(root, filepath.FromSlash(e.Path)), pkgType)
+		switch {
+		case !ok:
+			e.Reason = "no " + string(pkgType) + " headers found"
+		case validator.IsValidPackageName(name) != nil:
+			e.Reason = "directory name is not a valid package name"
+		case rawVersion == "":
+			e.Reason = "no version in headers"
+		default:
+			v, err := version.Normalize(rawVersion)
+			if err != nil {
+				e.Reason = fmt.Sprintf("unrecognized version %q", rawVersion)
+			}
+			e.Version = v
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// readPackageVersion returns the Version header of the plugin or theme in
+// dir, and false if dir has no main file with headers.
+func readPackageVersion(dir string, pkgType types.PackageType) (string, bool) {
+	if pkgType == types.TypeTheme {
+		h, err := parser.GetThemeHeaders(filepath.Join(dir, "style.css"))
+		if err != nil || h.Name == "" {
+			return "", false
+		}
+		return h.Version, true
+	}
+
+	_, h, err := parser.FindMainPluginFile(dir)
+	if err != nil {
+		return "", false
+	}
+	return h.Version, true
+}
+
+// scanMuPlugins lists must-use plugins. WordPress loads them without
+// activation or updates, so wpm leaves them to the site.
+func scanMuPlugins(root, dir string) ([]siteEntry, error) {
+	dirEntries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var entries []siteEntry
+	for _, de := range dirEntries {
+		name := de.Name()
+		if strings.HasPrefix(name, ".") || name == "index.php" {
+			continue
+		}
+		if !de.IsDir() && !strings.EqualFold(filepath.Ext(name), ".php") {
+			continue
+		}
+		entries = append(entries, siteEntry{
+			Type:   types.TypePlugin,
+			Name:   name,
+			Path:   path.Join(dir, name),
+			Reason: "must-use plugin",
+		})
+	}
+	return entries, nil
+}
+
+// matchSite looks up every manageable entry on the registry at its installed
+// version and marks those the registry doesn't have, or has as another type.
+// Only a canceled context is returned as an error; failed lookups are
+// recorded on the entry.
+func matchSite(ctx context.Context, entries []siteEntry, lookup packageLookup) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(siteLookupWorkers)
+
+	for i := range entries {
+		e := &entries[i]
+		if e.Reason != "" {
+			continue
+		}
+
+		g.Go(func() error {
+			pkg, err := lookup(gctx, e.Name, e.Version)
+			if err != nil {
+				if ctxErr := gctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				if httpErr, ok := errors.AsType[*api.HTTPError](err); ok && httpErr.StatusCode == http.StatusNotFound {
+					e.Reason = e.Version + " not found on the registry"
+				} else {
+					e.Reason = "registry lookup failed: " + output.Sanitize(err.Error())
+				}
+				return nil
+			}
+			if pkg.Type != e.Type {
+				e.Reason = "registry package is a " + output.Sanitize(string(pkg.Type))
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// buildSiteConfig returns a private config depending on every managed entry
+// at its installed version. Site configs are never published, so they are
+// not held to the package-level limits of Config.Validate.
+func buildSiteConfig(name string, l *layout.Layout, coreVersion string, entries []siteEntry) *wpmjson.Config {
+	cfg := wpmjson.New()
+	cfg.Name = name
+	cfg.Private = true
+
+	deps := types.Dependencies{}
+	for _, e := range entries {
+		if e.Reason == "" {
+			deps[e.Name] = e.Version
+		}
+	}
+	if len(deps) > 0 {
+		cfg.Dependencies = &deps
+	}
+
+	if l.Kind != layout.KindDefault {
+		applyLayout(cfg, l)
+	}
+	if coreVersion != "" {
+		if cfg.Config == nil {
+			cfg.Config = &types.PackageConfig{}
+		}
+		cfg.Config.Runtime = &types.Runtime{WP: coreVersion}
+	}
+
+	return cfg
+}
+
+func printSiteSummary(wpmCli command.Cli, cfg *wpmjson.Config, entries []siteEntry) {
+	managed := 0
+	if cfg.Dependencies != nil {
+		managed = len(*cfg.Dependencies)
+	}
+	_, _ = fmt.Fprintf(wpmCli.Out(), "pinned %d packages to their installed versions\n", managed)
+
+	var unmanaged []siteEntry
+	for _, e := range entries {
+		if e.Reason != "" {
+			unmanaged = append(unmanaged, e)
+		}
+	}
+	if len(unmanaged) == 0 {
+		return
+	}
+	slices.SortFunc(unmanaged, func(a, b siteEntry) int { return cmp.Compare(a.Path, b.Path) })
+
+	wpmCli.Output().PrettyErrorln(output.Text{
+		Plain: fmt.Sprintf("warn: %d unmanaged packages are left as they are:", len(unmanaged)),
+		Fancy: fmt.Sprintf("%s %d unmanaged packages are left as they are:", aec.YellowF.Apply("warn:"), len(unmanaged)),
+	})
+	for _, e := range unmanaged {
+		wpmCli.Output().PrettyErrorln(output.Text{
+			Plain: fmt.Sprintf("  %s (%s)", e.Path, e.Reason),
+			Fancy: fmt.Sprintf("  %s %s", e.Path, aec.LightBlackF.Apply("("+e.Reason+")")),
+		})
+	}
+}
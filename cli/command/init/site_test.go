@@ -0,0 +1,113 @@
+package init
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.wpm.so/cli/pkg/api"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/wp/layout"
+)
+
+func writeSiteFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSiteInit(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"wp-config.php":                                  "<?php\n",
+		"wp-content/plugins/index.php":                   "<?php // Silence is golden.",
+		"wp-content/plugins/hello.php":                   "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/",
+		"wp-content/plugins/akismet/akismet.php":         "<?php\n/*\nPlugin Name: Akismet\nVersion: 5.3\n*/",
+		"wp-content/plugins/custom-code/custom-code.php": "<?php\n/*\nPlugin Name: Custom Code\nVersion: 1.0.0\n*/",
+		"wp-content/plugins/no-version/main.php":         "<?php\n/*\nPlugin Name: No Version\n*/",
+		"wp-content/plugins/not-a-plugin/readme.txt":     "nothing here",
+		"wp-content/plugins/wrong-type/wrong-type.php":   "<?php\n/*\nPlugin Name: Wrong Type\nVersion: 2.0.0\n*/",
+		"wp-content/themes/twentytwentyfour/style.css":   "/*\nTheme Name: Twenty Twenty-Four\nVersion: 1.2\n*/",
+		"wp-content/themes/akismet/style.css":            "/*\nTheme Name: Clash\nVersion: 1.0.0\n*/",
+		"wp-content/mu-plugins/loader.php":               "<?php\n",
+	}
+	for name, content := range files {
+		writeSiteFile(t, root, name, content)
+	}
+
+	if !isSiteRoot(root) {
+		t.Fatal("isSiteRoot() = false for a directory with wp-config.php")
+	}
+
+	entries, err := scanSite(root, layout.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := func(_ context.Context, name, version string) (*manifest.Package, error) {
+		switch name {
+		case "akismet":
+			return &manifest.Package{Name: name, Version: version, Type: types.TypePlugin}, nil
+		case "twentytwentyfour":
+			return &manifest.Package{Name: name, Version: version, Type: types.TypeTheme}, nil
+		case "wrong-type":
+			return &manifest.Package{Name: name, Version: version, Type: "theme\x1b]0;pwned\x07"}, nil
+		}
+		return nil, &api.HTTPError{StatusCode: http.StatusNotFound}
+	}
+	if err := matchSite(context.Background(), entries, lookup); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := buildSiteConfig("my-site", layout.Default(), "6.9.0", entries)
+	if !cfg.Private {
+		t.Error("site config must be private")
+	}
+	if got := cfg.RuntimeWP(); got != "6.9.0" {
+		t.Errorf("runtime.wp = %q, want 6.9.0", got)
+	}
+
+	wantDeps := types.Dependencies{"akismet": "5.3.0", "twentytwentyfour": "1.2.0"}
+	if cfg.Dependencies == nil || len(*cfg.Dependencies) != len(wantDeps) {
+		t.Fatalf("dependencies = %v, want %v", cfg.Dependencies, wantDeps)
+	}
+	for name, v := range wantDeps {
+		if got := (*cfg.Dependencies)[name]; got != v {
+			t.Errorf("dependencies[%s] = %q, want %q", name, got, v)
+		}
+	}
+
+	wantUnmanaged := map[string]bool{
+		"wp-content/plugins/hello.php":     true,
+		"wp-content/plugins/custom-code":   true,
+		"wp-content/plugins/no-version":    true,
+		"wp-content/plugins/not-a-plugin":  true,
+		"wp-content/plugins/wrong-type":    true,
+		"wp-content/themes/akismet":        true,
+		"wp-content/mu-plugins/loader.php": true,
+	}
+	for _, e := range entries {
+		if e.Reason == "" {
+			continue
+		}
+		if !wantUnmanaged[e.Path] {
+			t.Errorf("unexpected unmanaged entry %s (%s)", e.Path, e.Reason)
+		}
+		if strings.ContainsAny(e.Reason, "\x1b\x07") {
+			t.Errorf("reason for %s has control characters: %q", e.Path, e.Reason)
+		}
+		delete(wantUnmanaged, e.Path)
+	}
+	for p := range wantUnmanaged {
+		t.Errorf("%s was not reported as unmanaged", p)
+	}
+}
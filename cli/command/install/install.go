@@ -14,10 +14,14 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
 	"go.wpm.so/cli/cli/version"
 	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/releaseage"
 	"go.wpm.so/cli/pkg/pm/workspace"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
 )
@@ -61,6 +65,7 @@ func NewInstallCommand(wpmCli command.Cli) *cobra.Command {
 			}
 			return err
 		},
+		ValidArgsFunction: completion.PackagesFromRegistry(wpmCli.RegistryClient),
 	}
 
 	flags := cmd.Flags()
@@ -89,7 +94,7 @@ func runInstall(ctx context.Context, wpmCli command.Cli, opts installOptions, pa
 		Fancy: aec.Bold.Apply("wpm install") + " " + aec.LightBlackF.Apply("v"+version.Version),
 	})
 
-	contentDir := wpmjson.New().ContentDir()
+	contentDir := wpmjson.NewInDir(cwd).ContentDir()
 	if probe, _ := wpmjson.Read(cwd); probe != nil {
 		contentDir = probe.ContentDir()
 	}
@@ -117,7 +122,7 @@ func runInstall(ctx context.Context, wpmCli command.Cli, opts installOptions, pa
 			return errRunHelp
 		}
 
-		cfg = wpmjson.New()
+		cfg = wpmjson.NewInDir(cwd)
 	}
 
 	configModified := false
@@ -170,14 +175,25 @@ func addPackages(ctx context.Context, config *wpmjson.Config, wpmCli command.Cli
 		return err
 	}
 
+	gate, err := releaseage.New(config.MinimumReleaseAge())
+	if err != nil {
+		return err
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(opts.networkConcurrency)
 
+	// Notes on versions held back by the minimum release age are printed once
+	// the progress indicator is gone.
+	var heldBack []output.Text
 	progress := wpmCli.Progress()
 	progress.StartProgressIndicator(wpmCli.Err())
 	defer func() {
 		progress.Stream(wpmCli.Err(), "")
 		progress.StopProgressIndicator()
+		for _, note := range heldBack {
+			wpmCli.Output().PrettyErrorln(note)
+		}
 	}()
 
 	if config.DevDependencies == nil {
@@ -204,9 +220,25 @@ func addPackages(ctx context.Context, config *wpmjson.Config, wpmCli command.Cli
 				return fmt.Errorf("failed to fetch package %s@%s: %w", name, versionOrTag, err)
 			}
 
+			var newest string
+			if gate != nil {
+				newest = manifest.Version
+				if manifest, err = eligibleVersion(ctx, client, gate, manifest, versionOrTag); err != nil {
+					return err
+				}
+			}
+
 			mu.Lock()
 			defer mu.Unlock()
 
+			if newest != "" && newest != manifest.Version {
+				note := fmt.Sprintf("%s@%s is %s, newer than the minimum release age; using %s", name, versionOrTag, output.Sanitize(newest), output.Sanitize(manifest.Version))
+				heldBack = append(heldBack, output.Text{
+					Plain: note,
+					Fancy: aec.YellowF.Apply(note),
+				})
+			}
+
 			switch {
 			case opts.saveDev:
 				(*config.DevDependencies)[name] = manifest.Version
@@ -229,6 +261,29 @@ func addPackages(ctx context.Context, config *wpmjson.Config, wpmCli command.Cli
 	return g.Wait()
 }
 
+// eligibleVersion returns m if the minimum release age lets it through. For a
+// dist tag whose version is too new, it returns the newest earlier release
+// that isn't instead; an explicit version is refused.
+func eligibleVersion(ctx context.Context, client registry.Client, gate *releaseage.Gate, m *manifest.Package, versionOrTag string) (*manifest.Package, error) {
+	err := gate.Check(m)
+	if err == nil {
+		return m, nil
+	}
+	name, version := output.Sanitize(m.Name), output.Sanitize(m.Version)
+	if _, tooNew := errors.AsType[*releaseage.TooNewError](err); !tooNew || validator.IsValidVersion(versionOrTag) == nil {
+		return nil, fmt.Errorf("refusing %s@%s: %w", name, version, err)
+	}
+
+	packument, perr := client.GetPackument(ctx, m.Name)
+	if perr != nil {
+		return nil, fmt.Errorf("failed to fetch versions of %s: %w", name, perr)
+	}
+	if older := gate.Newest(packument, m.Version); older != nil {
+		return older, nil
+	}
+	return nil, fmt.Errorf("refusing %s@%s: %w, and no earlier release is old enough", name, version, err)
+}
+
 func parsePackageArg(arg string) (string, string, error) {
 	if arg == "" {
 		return "", "", errors.New("package argument cannot be empty")
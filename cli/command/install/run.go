@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"maps"
-	"path/filepath"
 	"slices"
 	"strconv"
 
@@ -14,7 +13,10 @@ import (
 	"go.wpm.so/cli/cli/command"
 	"go.wpm.so/cli/pkg/output"
 	"go.wpm.so/cli/pkg/pm/installer"
+	"go.wpm.so/cli/pkg/pm/policy"
+	"go.wpm.so/cli/pkg/pm/registry"
 	"go.wpm.so/cli/pkg/pm/resolution"
+	"go.wpm.so/cli/pkg/pm/translations"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
 	"go.wpm.so/cli/pkg/pm/wpmlock"
 )
@@ -52,9 +54,10 @@ func installerProgress(out *output.Output) func(action installer.Action) {
 			// keep defaults
 		}
 
+		name, version := output.Sanitize(action.Name), output.Sanitize(action.Version)
 		out.Prettyln(output.Text{
-			Plain: fmt.Sprintf("%s %s@%s", actionStr, action.Name, action.Version),
-			Fancy: fmt.Sprintf("%s %s %s", color.Apply(actionStr), aec.Bold.Apply(action.Name), action.Version),
+			Plain: fmt.Sprintf("%s %s@%s", actionStr, name, version),
+			Fancy: fmt.Sprintf("%s %s %s", color.Apply(actionStr), aec.Bold.Apply(name), version),
 		})
 	}
 }
@@ -96,17 +99,47 @@ func Run(ctx context.Context, cwd string, wpmCli command.Cli, opts RunOptions) e
 	wpmCli.Out().WriteString("\n")
 
 	// absBinDir := filepath.Join(cwd, wpmCfg.BinDir())
-	absContentDir := filepath.Join(cwd, wpmCfg.ContentDir())
+	dirs := installer.DirsFor(cwd, wpmCfg)
 
-	plan := installer.CalculatePlan(lock, resolved, absContentDir, wpmCfg, opts.NoDev)
+	pol, err := policy.New(wpmCfg.InstallPolicy())
+	if err != nil {
+		return err
+	}
+
+	plan := installer.CalculatePlan(lock, resolved, dirs, wpmCfg, opts.NoDev)
+	installed := installer.Unchanged(plan, resolved, dirs)
 	if len(plan) == 0 {
+		violations, err := installer.CheckInstalled(pol, dirs, installed)
+		if err != nil {
+			return err
+		}
+		if len(violations) > 0 {
+			return &policy.Error{Violations: violations}
+		}
+
+		var synced int
+		if !opts.DryRun {
+			var syncErr error
+			synced, syncErr = syncTranslations(ctx, wpmCli, client, wpmCfg, lock, resolved, dirs, opts.NetworkConcurrency)
+			if synced > 0 {
+				if err := lock.Write(cwd); err != nil {
+					return fmt.Errorf("failed to save lockfile: %w", err)
+				}
+			}
+			if syncErr != nil {
+				return syncErr
+			}
+		}
+
 		if opts.SaveConfig {
 			if err := wpmCfg.Write(cwd); err != nil {
 				return fmt.Errorf("failed to save wpm.json: %w", err)
 			}
 		}
 
-		wpmCli.Out().WriteString("Already up-to-date!\n")
+		if synced == 0 {
+			wpmCli.Out().WriteString("Already up-to-date!\n")
+		}
 		return nil
 	}
 
@@ -116,7 +149,7 @@ func Run(ctx context.Context, cwd string, wpmCli command.Cli, opts RunOptions) e
 	}
 
 	// -- Actual Install --
-	inst, err := installer.New(ctx, absContentDir, opts.NetworkConcurrency, client, func(format string, args ...any) {
+	inst, err := installer.New(ctx, dirs, opts.NetworkConcurrency, client, pol, func(format string, args ...any) {
 		wpmCli.Output().ErrorWrite(fmt.Sprintf(format+"\n", args...))
 	})
 	if err != nil {
@@ -124,7 +157,7 @@ func Run(ctx context.Context, cwd string, wpmCli command.Cli, opts RunOptions) e
 	}
 	defer func() { _ = inst.Close() }()
 
-	if err := inst.InstallAll(ctx, plan, installerProgress(wpmCli.Output())); err != nil {
+	if err := inst.InstallAll(ctx, plan, installed, installerProgress(wpmCli.Output())); err != nil {
 		return fmt.Errorf("installation failed: %w", err)
 	}
 
@@ -133,9 +166,15 @@ func Run(ctx context.Context, cwd string, wpmCli command.Cli, opts RunOptions) e
 	// @todo: dependencies lifecycle scripts
 
 	updateLockPackages(lock, resolved)
+
+	_, syncErr := syncTranslations(ctx, wpmCli, client, wpmCfg, lock, resolved, dirs, opts.NetworkConcurrency)
+
 	if err := lock.Write(cwd); err != nil {
 		return fmt.Errorf("failed to save lockfile: %w", err)
 	}
+	if syncErr != nil {
+		return syncErr
+	}
 
 	// @todo: run root lifecycle scripts
 
@@ -165,16 +204,79 @@ func updateLockPackages(lock *wpmlock.Lockfile, resolved map[string]resolution.N
 	for _, name := range slices.Sorted(maps.Keys(resolved)) {
 		node := resolved[name]
 		lock.Packages[name] = wpmlock.LockPackage{
-			Version:      node.Version,
-			Signatures:   node.Signatures,
-			Digest:       node.Digest,
-			Type:         node.Type,
-			Bin:          node.Bin,
-			Dependencies: node.Dependencies,
+			Version:         node.Version,
+			Signatures:      node.Signatures,
+			AuthorSignature: node.AuthorSignature,
+			Digest:          node.Digest,
+			Type:            node.Type,
+			License:         node.License,
+			Bin:             node.Bin,
+			Dependencies:    node.Dependencies,
 		}
 	}
 }
 
+// syncTranslations installs translation packs for config.locales into the
+// languages directory and records them in the lockfile. It returns how many
+// packs were added or removed.
+func syncTranslations(
+	ctx context.Context,
+	wpmCli command.Cli,
+	client registry.Client,
+	wpmCfg *wpmjson.Config,
+	lock *wpmlock.Lockfile,
+	resolved map[string]resolution.Node,
+	dirs installer.Dirs,
+	concurrency int,
+) (int, error) {
+	locales := wpmCfg.Locales()
+	if len(locales) == 0 && len(lock.Translations) == 0 {
+		return 0, nil
+	}
+
+	var wanted []translations.Pack
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		node := resolved[name]
+		if !installer.IsInstalled(dirs, node.Type, name) {
+			continue
+		}
+		for _, locale := range locales {
+			wanted = append(wanted, translations.Pack{
+				Name:    name,
+				Version: node.Version,
+				Type:    node.Type,
+				Locale:  locale,
+			})
+		}
+	}
+
+	source := translations.NewSource(wpmCfg.TranslationsSource(), client, nil)
+	syncer := translations.New(dirs.Content, source, concurrency)
+
+	out := wpmCli.Output()
+	locked, changes, err := syncer.Sync(ctx, wanted, lock.Translations, func(c translations.Change) {
+		actionStr, color := "+", aec.GreenF
+		if c.Removed {
+			actionStr, color = "-", aec.RedF
+		}
+		name, version := output.Sanitize(c.Pack.Name), output.Sanitize(c.Pack.Version)
+		out.Prettyln(output.Text{
+			Plain: fmt.Sprintf("%s %s@%s [%s]", actionStr, name, version, c.Pack.Locale),
+			Fancy: fmt.Sprintf("%s %s %s %s", color.Apply(actionStr), aec.Bold.Apply(name), version, aec.Faint.Apply("["+c.Pack.Locale+"]")),
+		})
+	})
+
+	if len(locked) == 0 {
+		locked = nil
+	}
+	lock.Translations = locked
+
+	if err != nil {
+		return changes, fmt.Errorf("failed to install translations: %w", err)
+	}
+	return changes, nil
+}
+
 func printRunSummary(wpmCli command.Cli, trigger Trigger, count int) {
 	var action string
 	switch trigger {
@@ -0,0 +1,265 @@
+package licenses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/license"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+func NewLicensesCommand(wpmCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "licenses",
+		Short: "Summarize the licenses of the locked packages",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLicenses(cmd.Context(), wpmCli)
+		},
+	}
+	return cmd
+}
+
+// entry is a locked package and its license. Allowed is false when the
+// package violates the project's license policy.
+type entry struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	License    string `json:"license"` // as declared by the package
+	SPDX       string `json:"spdx"`    // normalized, or the declared text if unrecognized
+	Recognized bool   `json:"recognized"`
+	Allowed    bool   `json:"allowed"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+func runLicenses(ctx context.Context, wpmCli command.Cli) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	config, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return errors.New("no wpm.json found, so nothing to check")
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	if lock == nil {
+		return errors.New("no wpm.lock found, run 'wpm install' first to generate a lockfile")
+	}
+
+	declared, err := lockedLicenses(ctx, wpmCli, lock)
+	if err != nil {
+		return err
+	}
+
+	var policy *license.Policy
+	if p := config.LicensePolicy(); p != nil {
+		policy = &license.Policy{Allow: p.Allow, Deny: p.Deny}
+	}
+	entries := check(lock, declared, policy)
+
+	switch command.OutputFormat(wpmCli) {
+	case cliflags.FormatJSON:
+		if err := command.PrintJSON(wpmCli.Out(), entries); err != nil {
+			return err
+		}
+	case cliflags.FormatPlain:
+		printPlain(wpmCli.Out(), entries)
+	default:
+		if len(entries) == 0 {
+			wpmCli.Out().WriteString("No packages are locked.\n")
+			return nil
+		}
+		printSummary(wpmCli.Out(), wpmCli.Out().IsColorEnabled(), entries)
+	}
+
+	violations := 0
+	for _, e := range entries {
+		if !e.Allowed {
+			violations++
+		}
+	}
+	if violations > 0 {
+		verb := "violates"
+		if violations != 1 {
+			verb = "violate"
+		}
+		return cli.StatusError{
+			Status:     fmt.Sprintf("%d %s %s the license policy", violations, command.Pluralize("package", "s", violations), verb),
+			StatusCode: command.ExitCodeFindings,
+		}
+	}
+	return nil
+}
+
+// lockedLicenses returns the declared license of every locked package. Lockfiles
+// written before licenses were recorded are missing them, so those are read
+// from the packages' manifests in the registry.
+func lockedLicenses(ctx context.Context, wpmCli command.Cli, lock *wpmlock.Lockfile) (map[string]string, error) {
+	declared := make(map[string]string, len(lock.Packages))
+	var missing []string
+	for name, pkg := range lock.Packages {
+		if pkg.License == "" {
+			missing = append(missing, name)
+		}
+		declared[name] = output.Sanitize(pkg.License)
+	}
+	if len(missing) == 0 {
+		return declared, nil
+	}
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := wpmCli.Progress()
+	progress.StartProgressIndicator(wpmCli.Err())
+	defer func() {
+		progress.Stream(wpmCli.Err(), "")
+		progress.StopProgressIndicator()
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(16)
+	var mu sync.Mutex
+
+	slices.Sort(missing)
+	for i, name := range missing {
+		version := lock.Packages[name].Version
+		progress.Stream(wpmCli.Err(), fmt.Sprintf("  Resolving %s@%s [%d/%d]", name, version, i+1, len(missing)))
+
+		g.Go(func() error {
+			manifest, err := client.GetPackageManifest(ctx, name, version, false)
+			if err != nil {
+				return fmt.Errorf("failed to fetch package %s@%s: %w", name, version, err)
+			}
+			mu.Lock()
+			declared[name] = output.Sanitize(manifest.License)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return declared, nil
+}
+
+// check normalizes the license of every locked package and, with a policy,
+// checks it. Entries are ordered by package name.
+func check(lock *wpmlock.Lockfile, declared map[string]string, policy *license.Policy) []entry {
+	entries := make([]entry, 0, len(lock.Packages))
+	for _, name := range slices.Sorted(maps.Keys(lock.Packages)) {
+		e := entry{
+			Name:    name,
+			Version: output.Sanitize(lock.Packages[name].Version),
+			License: declared[name],
+			Allowed: true,
+		}
+		e.SPDX, e.Recognized = license.Normalize(e.License)
+		if policy != nil {
+			if err := policy.Check(e.License); err != nil {
+				e.Allowed = false
+				e.Reason = err.Error()
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// group is the packages sharing a normalized license.
+type group struct {
+	spdx       string
+	recognized bool
+	allowed    bool
+	entries    []entry
+}
+
+// groupByLicense groups entries by normalized license, most used first.
+func groupByLicense(entries []entry) []group {
+	index := make(map[string]int)
+	var groups []group
+	for _, e := range entries {
+		i, ok := index[e.SPDX]
+		if !ok {
+			i = len(groups)
+			index[e.SPDX] = i
+			groups = append(groups, group{spdx: e.SPDX, recognized: e.Recognized, allowed: e.Allowed})
+		}
+		groups[i].entries = append(groups[i].entries, e)
+	}
+
+	slices.SortStableFunc(groups, func(a, b group) int {
+		if n := len(b.entries) - len(a.entries); n != 0 {
+			return n
+		}
+		return strings.Compare(a.spdx, b.spdx)
+	})
+	return groups
+}
+
+func printSummary(out io.Writer, colorize bool, entries []entry) {
+	c := func(a aec.ANSI, s string) string {
+		if !colorize {
+			return s
+		}
+		return a.Apply(s)
+	}
+
+	for _, g := range groupByLicense(entries) {
+		count := strconv.Itoa(len(g.entries))
+		if !g.recognized && g.spdx != license.Unknown {
+			count += ", unrecognized"
+		}
+		header := c(aec.Bold, g.spdx) + " " + c(aec.Faint, "("+count+")")
+		if !g.allowed {
+			header = c(aec.RedF, "✘") + " " + header + " " + c(aec.RedF, "not allowed")
+		}
+		_, _ = fmt.Fprintln(out, header)
+
+		names := make([]string, len(g.entries))
+		for i, e := range g.entries {
+			names[i] = e.Name + "@" + e.Version
+		}
+		_, _ = fmt.Fprintf(out, "  %s\n", strings.Join(names, ", "))
+	}
+}
+
+// printPlain prints one tab-separated line per package: name, version,
+// normalized license, and "allowed" or "denied".
+func printPlain(out io.Writer, entries []entry) {
+	for _, e := range entries {
+		status := "allowed"
+		if !e.Allowed {
+			status = "denied"
+		}
+		_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", e.Name, e.Version, e.SPDX, status)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
 
 	"github.com/morikuni/aec"
 	"github.com/spf13/cobra"
@@ -16,6 +16,8 @@ import (
 	"go.wpm.so/cli/cli"
 	"go.wpm.so/cli/cli/command"
 	"go.wpm.so/cli/cli/command/completion"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/output"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmlock"
@@ -102,10 +104,25 @@ func runLs(wpmCli command.Cli, cwd string, opts lsOptions) (err error) {
 		maps.Copy(rootDeps, *config.DevDependencies)
 	}
 
-	groups := groupByType(rootDeps, lock, opts.filterType)
-	if len(groups) == 0 {
+	nodes := buildTree(rootDeps, lock, opts.depth)
+	nodes = slices.DeleteFunc(nodes, func(n lsNode) bool {
+		return opts.filterType != "" && n.typeLabel() != opts.filterType
+	})
+	if len(nodes) == 0 {
 		return fmt.Errorf("no %s dependencies found", opts.filterType)
 	}
+	if config.DevDependencies != nil {
+		for i := range nodes {
+			_, nodes[i].Dev = (*config.DevDependencies)[nodes[i].Name]
+		}
+	}
+
+	switch command.OutputFormat(wpmCli) {
+	case cliflags.FormatJSON:
+		return command.PrintJSON(wpmCli.Out(), lsResult{Name: root, Dependencies: nodes})
+	case cliflags.FormatPlain:
+		return printPlain(wpmCli.Out(), nodes)
+	}
 
 	out := bufio.NewWriter(wpmCli.Out())
 	defer func() {
@@ -116,14 +133,12 @@ func runLs(wpmCli command.Cli, cwd string, opts lsOptions) (err error) {
 
 	printer := &treePrinter{
 		out:      out,
-		lock:     lock,
-		maxDepth: opts.depth,
 		colorize: wpmCli.Out().IsColorEnabled(),
 	}
 
 	_, _ = fmt.Fprintln(out, root)
 
-	visited := make(map[string]bool)
+	groups := groupByType(nodes)
 	var prefix []byte
 	for i, g := range groups {
 		connector, indent := connectorMid, indentMid
@@ -138,7 +153,7 @@ func runLs(wpmCli command.Cli, cwd string, opts lsOptions) (err error) {
 		_, _ = fmt.Fprintf(out, "%s%s\n", connector, label)
 
 		prefix = append(prefix[:0], indent...)
-		printer.printLevel(g.deps, prefix, 0, visited)
+		printer.printLevel(g.nodes, prefix)
 	}
 
 	return nil
@@ -151,30 +166,116 @@ func depLen(d *types.Dependencies) int {
 	return len(*d)
 }
 
+// lsResult is the `wpm ls --format json` document.
+type lsResult struct {
+	Name         string   `json:"name"`
+	Dependencies []lsNode `json:"dependencies"`
+}
+
+// lsNode is a package in the dependency tree. Version is the locked version
+// and Requested the one asked for by the parent. Dev is only set on direct
+// dependencies.
+type lsNode struct {
+	Name         string   `json:"name"`
+	Version      string   `json:"version,omitempty"`
+	Requested    string   `json:"requested"`
+	Type         string   `json:"type,omitempty"`
+	Dev          bool     `json:"dev,omitempty"`
+	Missing      bool     `json:"missing,omitempty"`
+	Invalid      bool     `json:"invalid,omitempty"`
+	Cycle        bool     `json:"cycle,omitempty"`
+	Dependencies []lsNode `json:"dependencies,omitempty"`
+}
+
+func (n lsNode) typeLabel() string {
+	if n.Type == "" {
+		return unknownType
+	}
+	return n.Type
+}
+
+// buildTree resolves deps against the lockfile, down to maxDepth levels below
+// them when maxDepth is not negative.
+func buildTree(deps map[string]string, lock *wpmlock.Lockfile, maxDepth int) []lsNode {
+	return buildLevel(deps, lock, maxDepth, 0, make(map[string]bool))
+}
+
+func buildLevel(deps map[string]string, lock *wpmlock.Lockfile, maxDepth, depth int, visited map[string]bool) []lsNode {
+	if maxDepth >= 0 && depth > maxDepth {
+		return nil
+	}
+
+	nodes := make([]lsNode, 0, len(deps))
+	for _, name := range slices.Sorted(maps.Keys(deps)) {
+		node := lsNode{Name: name, Requested: deps[name]}
+
+		pkg, ok := lock.Packages[name]
+		if !ok {
+			node.Missing = true
+			nodes = append(nodes, node)
+			continue
+		}
+
+		// Versions and types in the lockfile were taken from the registry.
+		node.Version = output.Sanitize(pkg.Version)
+		node.Type = output.Sanitize(pkg.Type.String())
+		node.Invalid = pkg.Version != node.Requested
+		node.Cycle = visited[name]
+
+		if !node.Cycle && pkg.Dependencies != nil {
+			visited[name] = true
+			node.Dependencies = buildLevel(*pkg.Dependencies, lock, maxDepth, depth+1, visited)
+			delete(visited, name)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// printPlain writes one tab-separated line per node, depth first:
+// depth, name, version, type and status.
+func printPlain(w io.Writer, nodes []lsNode) error {
+	out := bufio.NewWriter(w)
+	var walk func(nodes []lsNode, depth int)
+	walk = func(nodes []lsNode, depth int) {
+		for _, n := range nodes {
+			version := n.Version
+			if version == "" {
+				version = n.Requested
+			}
+			_, _ = fmt.Fprintf(out, "%d\t%s\t%s\t%s\t%s\n", depth, n.Name, version, n.typeLabel(), n.status())
+			walk(n.Dependencies, depth+1)
+		}
+	}
+	walk(nodes, 0)
+	return out.Flush()
+}
+
+func (n lsNode) status() string {
+	switch {
+	case n.Missing:
+		return "missing"
+	case n.Invalid:
+		return "invalid"
+	case n.Cycle:
+		return "cycle"
+	}
+	return "ok"
+}
+
 // depGroup is a set of direct dependencies that share a package type.
 type depGroup struct {
 	label string
-	deps  map[string]string
+	nodes []lsNode
 }
 
 // groupByType partitions direct dependencies by their locked package type,
 // known types first then any others sorted.
-func groupByType(deps map[string]string, lock *wpmlock.Lockfile, filter string) []depGroup {
-	buckets := make(map[string]map[string]string)
-	for name, version := range deps {
-		label := unknownType
-		if pkg, ok := lock.Packages[name]; ok && pkg.Type != "" {
-			label = pkg.Type.String()
-		}
-		if filter != "" && label != filter {
-			continue
-		}
-		bucket := buckets[label]
-		if bucket == nil {
-			bucket = make(map[string]string)
-			buckets[label] = bucket
-		}
-		bucket[name] = version
+func groupByType(nodes []lsNode) []depGroup {
+	buckets := make(map[string][]lsNode)
+	for _, n := range nodes {
+		label := n.typeLabel()
+		buckets[label] = append(buckets[label], n)
 	}
 
 	groups := make([]depGroup, 0, len(buckets))
@@ -185,12 +286,7 @@ func groupByType(deps map[string]string, lock *wpmlock.Lockfile, filter string)
 		}
 	}
 
-	rest := make([]string, 0, len(buckets))
-	for label := range buckets {
-		rest = append(rest, label)
-	}
-	sort.Strings(rest)
-	for _, label := range rest {
+	for _, label := range slices.Sorted(maps.Keys(buckets)) {
 		groups = append(groups, depGroup{label, buckets[label]})
 	}
 
@@ -199,79 +295,57 @@ func groupByType(deps map[string]string, lock *wpmlock.Lockfile, filter string)
 
 type treePrinter struct {
 	out      io.Writer
-	lock     *wpmlock.Lockfile
-	maxDepth int
 	colorize bool
 }
 
 // printLevel renders one level of the tree.
-func (p *treePrinter) printLevel(deps map[string]string, prefix []byte, depth int, visited map[string]bool) {
-	if p.maxDepth >= 0 && depth > p.maxDepth {
-		return
-	}
-
-	keys := make([]string, 0, len(deps))
-	for k := range deps {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	for i, name := range keys {
-		isLast := i == len(keys)-1
-		connector := connectorMid
+func (p *treePrinter) printLevel(nodes []lsNode, prefix []byte) {
+	for i, node := range nodes {
+		isLast := i == len(nodes)-1
+		connector, indent := connectorMid, indentMid
 		if isLast {
-			connector = connectorEnd
+			connector, indent = connectorEnd, indentEnd
 		}
 
-		isCycle := visited[name]
-		info, subDeps, isMissing := p.formatNode(name, deps[name], isCycle)
-		_, _ = fmt.Fprintf(p.out, "%s%s%s\n", prefix, connector, info)
+		_, _ = fmt.Fprintf(p.out, "%s%s%s\n", prefix, connector, p.formatNode(node))
 
-		if isMissing || isCycle || len(subDeps) == 0 {
+		if len(node.Dependencies) == 0 {
 			continue
 		}
 
-		indent := indentMid
-		if isLast {
-			indent = indentEnd
-		}
-
 		mark := len(prefix)
 		prefix = append(prefix, indent...)
-		visited[name] = true
-		p.printLevel(subDeps, prefix, depth+1, visited)
-		delete(visited, name)
+		p.printLevel(node.Dependencies, prefix)
 		prefix = prefix[:mark]
 	}
 }
 
-// formatNode renders a single node and returns its sub-dependencies and whether
-// the package is missing from the lockfile.
-func (p *treePrinter) formatNode(name, requestedVersion string, isCycle bool) (info string, subDeps map[string]string, isMissing bool) {
-	pkg, ok := p.lock.Packages[name]
-	if !ok {
+// formatNode renders a single node.
+func (p *treePrinter) formatNode(node lsNode) string {
+	if node.Missing {
 		unmet := "UNMET DEPENDENCY"
 		if p.colorize {
 			unmet = aec.RedF.Apply(unmet)
 		}
-		return name + "@" + requestedVersion + " " + unmet, nil, true
+		return node.Name + "@" + node.Requested + " " + unmet
 	}
 
+	var info string
 	if p.colorize {
-		info = name + aec.LightBlackF.Apply("@"+pkg.Version)
+		info = node.Name + aec.LightBlackF.Apply("@"+node.Version)
 	} else {
-		info = name + "@" + pkg.Version
+		info = node.Name + "@" + node.Version
 	}
 
-	if pkg.Version != requestedVersion {
-		invalid := "(invalid: \"" + requestedVersion + "\")"
+	if node.Invalid {
+		invalid := "(invalid: \"" + node.Requested + "\")"
 		if p.colorize {
 			invalid = aec.RedF.Apply(invalid)
 		}
 		info += " " + invalid
 	}
 
-	if isCycle {
+	if node.Cycle {
 		cycle := "(cycle)"
 		if p.colorize {
 			cycle = aec.MagentaF.Apply(cycle)
@@ -279,8 +353,5 @@ func (p *treePrinter) formatNode(name, requestedVersion string, isCycle bool) (i
 		info += " " + cycle
 	}
 
-	if pkg.Dependencies != nil {
-		subDeps = *pkg.Dependencies
-	}
-	return info, subDeps, false
+	return info
 }
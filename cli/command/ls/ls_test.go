@@ -0,0 +1,41 @@
+package ls
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+func TestBuildTree(t *testing.T) {
+	lock := wpmlock.New()
+	lock.Packages["woocommerce"] = wpmlock.LockPackage{
+		Version:      "9.0.0",
+		Type:         types.TypePlugin,
+		Dependencies: &types.Dependencies{"action-scheduler": "3.8.0", "missing": "1.0.0"},
+	}
+	lock.Packages["action-scheduler"] = wpmlock.LockPackage{
+		Version:      "3.7.0",
+		Type:         types.TypePlugin,
+		Dependencies: &types.Dependencies{"woocommerce": "9.0.0"},
+	}
+
+	nodes := buildTree(map[string]string{"woocommerce": "9.0.0"}, lock, -1)
+
+	got, err := json.Marshal(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"name":"woocommerce","version":"9.0.0","requested":"9.0.0","type":"plugin","dependencies":[` +
+		`{"name":"action-scheduler","version":"3.7.0","requested":"3.8.0","type":"plugin","invalid":true,"dependencies":[` +
+		`{"name":"woocommerce","version":"9.0.0","requested":"9.0.0","type":"plugin","cycle":true}]},` +
+		`{"name":"missing","requested":"1.0.0","missing":true}]}]`
+	if string(got) != want {
+		t.Errorf("buildTree() =\n%s\nwant\n%s", got, want)
+	}
+
+	if nodes := buildTree(map[string]string{"woocommerce": "9.0.0"}, lock, 0); len(nodes[0].Dependencies) != 0 {
+		t.Errorf("depth 0 kept %d sub-dependencies", len(nodes[0].Dependencies))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/Masterminds/semver/v3"
@@ -16,31 +17,49 @@ import (
 
 	"go.wpm.so/cli/cli"
 	"go.wpm.so/cli/cli/command"
+	cliflags "go.wpm.so/cli/cli/flags"
 	"go.wpm.so/cli/cli/version"
 	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/releaseage"
+	"go.wpm.so/cli/pkg/pm/resolution"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmlock"
+	"go.wpm.so/cli/pkg/wp/changelog"
 )
 
+type outdatedOptions struct {
+	all       bool
+	changelog bool
+}
+
 func NewOutdatedCommand(wpmCli command.Cli) *cobra.Command {
+	var opts outdatedOptions
+
 	cmd := &cobra.Command{
-		Use:   "outdated",
+		Use:   "outdated [OPTIONS]",
 		Short: "Check for outdated dependencies",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runOutdated(cmd.Context(), wpmCli)
+			return runOutdated(cmd.Context(), wpmCli, opts)
 		},
 	}
+
+	cmd.Flags().BoolVarP(&opts.all, "all", "a", false, "Include transitive dependencies")
+	cmd.Flags().BoolVar(&opts.changelog, "changelog", false, "Show the changelog entries since the locked version")
+
 	return cmd
 }
 
 type depCheck struct {
-	name    string
-	version string
-	isDev   bool
+	name         string
+	version      string
+	isDev        bool
+	isTransitive bool
 }
 
-func runOutdated(ctx context.Context, wpmCli command.Cli) error {
+func runOutdated(ctx context.Context, wpmCli command.Cli, opts outdatedOptions) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current working directory: %w", err)
@@ -63,63 +82,185 @@ func runOutdated(ctx context.Context, wpmCli command.Cli) error {
 		return errors.New("no wpm.lock found, run 'wpm install' first to generate a lockfile")
 	}
 
-	wpmCli.Output().Prettyln(output.Text{
-		Plain: "wpm outdated v" + version.Version,
-		Fancy: aec.Bold.Apply("wpm outdated") + " " + aec.LightBlackF.Apply("v"+version.Version),
-	})
+	format := command.OutputFormat(wpmCli)
+	if format == cliflags.FormatTable {
+		wpmCli.Output().Prettyln(output.Text{
+			Plain: "wpm outdated v" + version.Version,
+			Fancy: aec.Bold.Apply("wpm outdated") + " " + aec.LightBlackF.Apply("v"+version.Version),
+		})
+	}
+
+	results, err := Find(ctx, wpmCli, config, lock, FindOptions{All: opts.all, Changelog: opts.changelog})
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case cliflags.FormatJSON:
+		if results == nil {
+			results = []Entry{}
+		}
+		if err := command.PrintJSON(wpmCli.Out(), results); err != nil {
+			return err
+		}
+	case cliflags.FormatPlain:
+		printPlainList(wpmCli.Out(), results)
+	default:
+		if !hasLockedDependencies(config, lock) {
+			return nil
+		}
+		wpmCli.Out().WriteString("\n")
+		if len(results) == 0 {
+			wpmCli.Out().WriteString("Already up-to-date!\n")
+			return nil
+		}
+		printOutdatedList(wpmCli.Out(), wpmCli.Out().IsColorEnabled(), results)
+	}
+
+	if len(results) > 0 {
+		return cli.StatusError{
+			Status:     fmt.Sprintf("%d outdated %s", len(results), command.Pluralize("package", "s", len(results))),
+			StatusCode: command.ExitCodeFindings,
+		}
+	}
+	return nil
+}
+
+func isRootDependency(config *wpmjson.Config, name string) bool {
+	if config.Dependencies != nil {
+		if _, ok := (*config.Dependencies)[name]; ok {
+			return true
+		}
+	}
+	if config.DevDependencies != nil {
+		if _, ok := (*config.DevDependencies)[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is an outdated package. It is also an element of the
+// `wpm outdated --format json` array.
+type Entry struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Dev          bool   `json:"dev"`
+	Transitive   bool   `json:"transitive"`
+	Current      string `json:"current"`
+	Wanted       string `json:"wanted"` // newest version that runs on the project's runtime and is old enough, if any
+	Latest       string `json:"latest"`
+	DiffType     string `json:"diffType"`               // major, minor, patch, or unknown
+	Incompatible string `json:"incompatible,omitempty"` // why latest doesn't run on the project's runtime
+	TooNew       string `json:"tooNew,omitempty"`       // why latest is held back by the minimum release age
+
+	Changelog []changelog.Release `json:"changelog,omitempty"` // releases after current, up to latest
+}
 
+// FindOptions controls what Find checks and collects.
+type FindOptions struct {
+	All       bool // include transitive dependencies
+	Changelog bool // collect changelog entries from the latest readme
+}
+
+// Find returns the dependencies of config whose locked version is behind the
+// latest release, sorted by name.
+func Find(ctx context.Context, wpmCli command.Cli, config *wpmjson.Config, lock *wpmlock.Lockfile, opts FindOptions) ([]Entry, error) {
 	var checks []depCheck
 
 	if config.Dependencies != nil {
 		for name := range *config.Dependencies {
 			if pkg, ok := lock.Packages[name]; ok {
-				checks = append(checks, depCheck{name, pkg.Version, false})
+				checks = append(checks, depCheck{name, pkg.Version, false, false})
 			}
 		}
 	}
 	if config.DevDependencies != nil {
 		for name := range *config.DevDependencies {
 			if pkg, ok := lock.Packages[name]; ok {
-				checks = append(checks, depCheck{name, pkg.Version, true})
+				checks = append(checks, depCheck{name, pkg.Version, true, false})
+			}
+		}
+	}
+	if opts.All {
+		for name, pkg := range lock.Packages {
+			if !isRootDependency(config, name) {
+				checks = append(checks, depCheck{name, pkg.Version, false, true})
 			}
 		}
 	}
 
 	if len(checks) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	results, err := findOutdatedPackages(ctx, wpmCli, checks)
+	var runtime *types.Runtime
+	if config.RuntimeStrict() {
+		runtime = config.Config.Runtime
+	}
+	gate, err := releaseage.New(config.MinimumReleaseAge())
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	wpmCli.Out().WriteString("\n")
-
-	if len(results) == 0 {
-		wpmCli.Out().WriteString("Already up-to-date!\n")
-		return nil
+	results, err := findOutdatedPackages(ctx, wpmCli, checks, runtime, gate, opts.Changelog)
+	if err != nil {
+		return nil, err
 	}
 
+	// Versions, runtime errors and changelogs all come from the registry.
+	output.SanitizeValue(&results)
+
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].name < results[j].name
+		return results[i].Name < results[j].Name
 	})
+	return results, nil
+}
 
-	printOutdatedList(wpmCli.Out(), wpmCli.Out().IsColorEnabled(), results)
+// hasLockedDependencies reports whether any direct dependency is in the lockfile.
+func hasLockedDependencies(config *wpmjson.Config, lock *wpmlock.Lockfile) bool {
+	for name := range lock.Packages {
+		if isRootDependency(config, name) {
+			return true
+		}
+	}
+	return false
+}
 
-	return nil
+// printPlainList writes one tab-separated line per outdated dependency:
+// name, current, latest, diff type, package type, "dev" or "prod", wanted,
+// "direct" or "transitive", and "compatible" or "incompatible".
+func printPlainList(out io.Writer, results []Entry) {
+	for _, r := range results {
+		group := "prod"
+		if r.Dev {
+			group = "dev"
+		}
+		scope := "direct"
+		if r.Transitive {
+			scope = "transitive"
+		}
+		compat := "compatible"
+		if r.Incompatible != "" {
+			compat = "incompatible"
+		}
+		_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Name, r.Current, r.Latest, r.DiffType, r.Type, group, orDash(r.Wanted), scope, compat)
+	}
 }
 
-type outdatedInfo struct {
-	name     string
-	current  string
-	latest   string
-	pkgType  string
-	isDev    bool
-	diffType string // major, minor, patch, or unknown
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
 
-func findOutdatedPackages(ctx context.Context, wpmCli command.Cli, checks []depCheck) ([]outdatedInfo, error) {
+// findOutdatedPackages compares checks with their latest release. When runtime
+// is set, a latest release that doesn't run on it is flagged, and wanted is
+// the newest release that does. Likewise, with a gate, a latest release that
+// is too new is flagged and wanted is the newest one old enough. With withChangelog, the latest readme's
+// changelog entries since the current version are kept.
+func findOutdatedPackages(ctx context.Context, wpmCli command.Cli, checks []depCheck, runtime *types.Runtime, gate *releaseage.Gate, withChangelog bool) ([]Entry, error) {
 	client, err := wpmCli.RegistryClient()
 	if err != nil {
 		return nil, err
@@ -137,7 +278,7 @@ func findOutdatedPackages(ctx context.Context, wpmCli command.Cli, checks []depC
 
 	var (
 		mu      sync.Mutex
-		results []outdatedInfo
+		results []Entry
 	)
 
 	for i, check := range checks {
@@ -153,15 +294,37 @@ func findOutdatedPackages(ctx context.Context, wpmCli command.Cli, checks []depC
 			latestVer, err2 := semver.NewVersion(manifest.Version)
 
 			if err1 == nil && err2 == nil && latestVer.GreaterThan(currentVer) {
-				diff := getDiffType(check.version, manifest.Version)
-
-				info := outdatedInfo{
-					name:     check.name,
-					current:  check.version,
-					latest:   manifest.Version,
-					pkgType:  string(manifest.Type),
-					isDev:    check.isDev,
-					diffType: diff,
+				info := Entry{
+					Name:       check.name,
+					Type:       string(manifest.Type),
+					Dev:        check.isDev,
+					Transitive: check.isTransitive,
+					Current:    check.version,
+					Wanted:     manifest.Version,
+					Latest:     manifest.Version,
+					DiffType:   DiffType(check.version, manifest.Version),
+				}
+
+				if withChangelog && manifest.Readme != "" {
+					// A readme without a usable changelog just leaves the column empty.
+					info.Changelog, _ = changelog.Between(manifest.Readme, check.version, manifest.Version)
+				}
+
+				if err := resolution.CheckRuntime(manifest.Requires, runtime); err != nil {
+					info.Incompatible = err.Error()
+				}
+				if gate != nil {
+					if err := gate.Check(manifest); err != nil {
+						info.TooNew = err.Error()
+					}
+				}
+
+				if info.Incompatible != "" || info.TooNew != "" {
+					packument, err := client.GetPackument(ctx, check.name)
+					if err != nil {
+						return fmt.Errorf("failed to fetch versions of %s: %w", check.name, err)
+					}
+					info.Wanted = newestCompatible(packument, latestVer, runtime, gate)
 				}
 
 				mu.Lock()
@@ -180,7 +343,35 @@ func findOutdatedPackages(ctx context.Context, wpmCli command.Cli, checks []depC
 	return results, nil
 }
 
-func getDiffType(current, latest string) string {
+// newestCompatible returns the newest release in p, no newer than latest, that
+// runs on runtime and that gate lets through, or "" if there is none.
+// Pre-releases are skipped.
+func newestCompatible(p *manifest.Packument, latest *semver.Version, runtime *types.Runtime, gate *releaseage.Gate) string {
+	var newest *semver.Version
+	for v, pkg := range p.Versions {
+		sv, err := semver.StrictNewVersion(v)
+		if err != nil || pkg == nil || sv.Prerelease() != "" || sv.GreaterThan(latest) {
+			continue
+		}
+		if newest != nil && !sv.GreaterThan(newest) {
+			continue
+		}
+		if gate != nil && gate.Check(pkg) != nil {
+			continue
+		}
+		if resolution.CheckRuntime(pkg.Requires, runtime) == nil {
+			newest = sv
+		}
+	}
+	if newest == nil {
+		return ""
+	}
+	return newest.String()
+}
+
+// DiffType classifies the bump from current to latest as major, minor, patch
+// or unknown.
+func DiffType(current, latest string) string {
 	currV, err1 := semver.NewVersion(current)
 	latestV, err2 := semver.NewVersion(latest)
 
@@ -201,7 +392,7 @@ func getDiffType(current, latest string) string {
 	return "unknown"
 }
 
-func printOutdatedList(out io.Writer, colorize bool, results []outdatedInfo) {
+func printOutdatedList(out io.Writer, colorize bool, results []Entry) {
 	c := func(a aec.ANSI, s string) string {
 		if !colorize {
 			return s
@@ -210,20 +401,24 @@ func printOutdatedList(out io.Writer, colorize bool, results []outdatedInfo) {
 	}
 
 	for i, r := range results {
-		nameStr := c(aec.Bold, r.name)
-		typeStr := c(aec.CyanF, fmt.Sprintf("[%s]", r.pkgType))
+		nameStr := c(aec.Bold, r.Name)
+		typeStr := c(aec.CyanF, fmt.Sprintf("[%s]", r.Type))
 
 		devStr := ""
-		if r.isDev {
+		if r.Dev {
 			devStr = c(aec.Faint, "(dev)")
 		}
 
+		if r.Transitive {
+			devStr = c(aec.Faint, "(transitive)")
+		}
+
 		_, _ = fmt.Fprintf(out, "%s %s %s\n", nameStr, typeStr, devStr)
 
 		var diffLabel string
 		var severityColor aec.ANSI
 
-		switch r.diffType {
+		switch r.DiffType {
 		case "major":
 			severityColor = aec.RedF
 			diffLabel = "(major update)"
@@ -243,17 +438,56 @@ func printOutdatedList(out io.Writer, colorize bool, results []outdatedInfo) {
 
 		_, _ = fmt.Fprintf(out, "%s current: %s\n",
 			treeBranch,
-			r.current,
+			r.Current,
+		)
+
+		wanted := r.Wanted
+		if wanted == "" {
+			wanted = "none"
+		}
+		_, _ = fmt.Fprintf(out, "%s wanted:  %s\n",
+			treeBranch,
+			wanted,
 		)
 
 		_, _ = fmt.Fprintf(out, "%s latest:  %s %s\n",
 			treeEnd,
-			c(severityColor, r.latest),  // Colorized Version
+			c(severityColor, r.Latest),  // Colorized Version
 			c(severityColor, diffLabel), // Colorized Label
 		)
 
+		if r.Incompatible != "" {
+			_, _ = fmt.Fprintf(out, "    %s\n", c(aec.RedF, "latest "+r.Incompatible))
+		}
+		if r.TooNew != "" {
+			_, _ = fmt.Fprintf(out, "    %s\n", c(aec.YellowF, "latest "+r.TooNew))
+		}
+
+		printChangelog(out, c, r.Changelog)
+
 		if i < len(results)-1 {
 			_, _ = fmt.Fprintln(out, "")
 		}
 	}
 }
+
+// printChangelog lists releases below an entry with the first line of their
+// notes. Upgrade notices are highlighted.
+func printChangelog(out io.Writer, c func(aec.ANSI, string) string, releases []changelog.Release) {
+	for _, r := range releases {
+		summary := firstLine(r.Notes)
+		_, _ = fmt.Fprintf(out, "    %s %s\n", c(aec.Bold, r.Version), summary)
+		if r.UpgradeNotice != "" {
+			_, _ = fmt.Fprintf(out, "      %s\n", c(aec.YellowF, "! "+firstLine(r.UpgradeNotice)))
+		}
+	}
+}
+
+func firstLine(s string) string {
+	for line := range strings.SplitSeq(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package outdated
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func TestNewestCompatible(t *testing.T) {
+	packument := &manifest.Packument{
+		Versions: map[string]*manifest.Package{
+			"1.0.0":       {Requires: &types.Requires{PHP: ">=7.4"}},
+			"1.5.0":       {Requires: &types.Requires{PHP: ">=7.4", WP: ">=6.0"}},
+			"1.6.0-beta1": {Requires: &types.Requires{PHP: ">=7.4"}},
+			"2.0.0":       {Requires: &types.Requires{PHP: ">=8.1"}},
+			"2.1.0":       {Requires: &types.Requires{PHP: ">=7.4"}},
+		},
+	}
+	latest := semver.MustParse("2.0.0")
+
+	tests := []struct {
+		runtime *types.Runtime
+		want    string
+	}{
+		{&types.Runtime{PHP: "7.4", WP: "6.5"}, "1.5.0"},
+		{&types.Runtime{PHP: "7.4", WP: "5.9"}, "1.0.0"},
+		{&types.Runtime{PHP: "8.2"}, "2.0.0"},
+		{&types.Runtime{PHP: "7.0"}, ""},
+	}
+	for _, tt := range tests {
+		if got := newestCompatible(packument, latest, tt.runtime, nil); got != tt.want {
+			t.Errorf("newestCompatible(%+v) = %q, want %q", *tt.runtime, got, tt.want)
+		}
+	}
+}
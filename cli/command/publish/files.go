@@ -0,0 +1,131 @@
+package publish
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/moby/patternmatcher"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/wp/parser"
+)
+
+// includeFiles returns the paths in dir selected by the files allowlist in
+// wpm.json, for archive.TarOptions.IncludeFiles, or nil to pack the whole
+// directory if there is no allowlist.
+//
+// Paths matched by a glob are left out if .wpmignore ignores them, while
+// paths listed as they are always count. .wpmignore still applies inside the
+// selected directories. wpm.json, the readme and the package's main file are
+// always included; warn is called if the allowlist leaves the main file out.
+func includeFiles(dir string, wpmJson *wpmjson.Config, ignorePatterns []string, warn func(string)) ([]string, error) {
+	if len(wpmJson.Files) == 0 {
+		return nil, nil
+	}
+
+	files, err := patternmatcher.New(wpmJson.Files)
+	if err != nil {
+		return nil, fmt.Errorf("invalid files in %s: %w", wpmjson.ConfigFile, err)
+	}
+	ignore, err := patternmatcher.New(ignorePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	literal := make(map[string]bool, len(wpmJson.Files))
+	for _, f := range wpmJson.Files {
+		if !strings.ContainsAny(f, `*?[\`) {
+			literal[filepath.Clean(f)] = true
+		}
+	}
+
+	var include []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		if matched, err := files.MatchesOrParentMatches(rel); err != nil || !matched {
+			return err
+		}
+		if !literal[rel] {
+			// Keep walking an ignored directory, since .wpmignore may
+			// re-include some of its contents.
+			if ignored, err := ignore.MatchesOrParentMatches(rel); err != nil || ignored {
+				return err
+			}
+		}
+
+		include = append(include, rel)
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to match files in %s: %w", wpmjson.ConfigFile, err)
+	}
+
+	always, err := alwaysIncluded(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range always {
+		if !slices.Contains(include, name) {
+			include = append(include, name)
+		}
+	}
+
+	if main := mainFile(dir, wpmJson.Type); main != "" && !slices.Contains(include, main) {
+		warn(fmt.Sprintf("files in %s leaves out the main %s file %s, packing it anyway", wpmjson.ConfigFile, wpmJson.Type, main))
+		include = append(include, main)
+	}
+
+	return include, nil
+}
+
+// alwaysIncluded returns wpm.json and the readme files at the top of dir.
+func alwaysIncluded(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(entry.Name()) {
+		case wpmjson.ConfigFile, "readme.md", "readme.txt":
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// mainFile returns the file WordPress loads a package by: the top-level PHP
+// file with a Plugin Name header for plugins, or style.css for themes. It
+// returns "" if there is none.
+func mainFile(dir string, pkgType types.PackageType) string {
+	switch pkgType {
+	case types.TypeTheme:
+		if _, err := os.Stat(filepath.Join(dir, "style.css")); err == nil {
+			return "style.css"
+		}
+	case types.TypePlugin:
+		if path, _, err := parser.FindMainPluginFile(dir); err == nil {
+			return filepath.Base(path)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func TestIncludeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"wpm.json":       "{}",
+		"README.md":      "# Hello Dolly",
+		"hello.php":      "<?php\n/**\n * Plugin Name: Hello Dolly\n */\n",
+		"uninstall.php":  "<?php",
+		"src/lyrics.php": "<?php",
+		"assets/a.css":   "",
+		"assets/b.css":   "",
+		"assets/c.js":    "",
+		"tests/test.php": "<?php",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wpmJson := &wpmjson.Config{
+		Type:  types.TypePlugin,
+		Files: []string{"src", "assets/*.css", "uninstall.php"},
+	}
+	var warnings []string
+	got, err := includeFiles(dir, wpmJson, []string{"assets/b.css", "uninstall.php"}, func(msg string) {
+		warnings = append(warnings, msg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(got)
+	want := []string{"README.md", "assets/a.css", "hello.php", "src", "uninstall.php", "wpm.json"}
+	if !slices.Equal(got, want) {
+		t.Errorf("includeFiles() = %q, want %q", got, want)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %q, want one for hello.php", warnings)
+	}
+
+	if got, err := includeFiles(dir, &wpmjson.Config{Type: types.TypePlugin}, nil, nil); got != nil || err != nil {
+		t.Errorf("includeFiles() without files = %q, %v; want nil, nil", got, err)
+	}
+}
@@ -0,0 +1,182 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/pm/wpmignore"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+)
+
+type packOptions struct {
+	out     string
+	list    bool
+	verbose bool
+}
+
+func NewPackCommand(wpmCli command.Cli) *cobra.Command {
+	var opts packOptions
+
+	cmd := &cobra.Command{
+		Use:   "pack [OPTIONS]",
+		Short: "Pack the package into the tarball publish would upload",
+		Args:  cli.NoArgs,
+		RunE:  func(cmd *cobra.Command, args []string) error { return runPack(cmd.Context(), wpmCli, opts) },
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(&opts.out, "out", "o", ".", "Write the tarball and its JSON sidecar to this directory")
+	flags.BoolVar(&opts.list, "list", false, "Print the files that would be packed, without writing anything")
+	flags.BoolVar(&opts.verbose, "verbose", false, "Enable verbose output")
+	cmd.MarkFlagsMutuallyExclusive("out", "list")
+
+	return cmd
+}
+
+func runPack(ctx context.Context, wpmCli command.Cli, opts packOptions) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	wpmJson, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if wpmJson == nil {
+		return errors.New("no wpm.json found in the current directory")
+	}
+	// Private packages can be packed, only publishing them is refused.
+	if err := wpmJson.Validate(); err != nil {
+		return err
+	}
+
+	if opts.list {
+		return listPacked(ctx, wpmCli, cwd, wpmJson)
+	}
+
+	_, _ = fmt.Fprintf(wpmCli.Err(), "📦 %s@%s\n\n", wpmJson.Name, wpmJson.Version)
+
+	if err := os.MkdirAll(opts.out, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Pack into a temporary file next to the tarball, so that an interrupted
+	// pack never leaves a partial tarball behind under the final name.
+	name := tarballName(wpmJson.Name, wpmJson.Version)
+	tempFile, err := os.CreateTemp(opts.out, "."+name+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary tarball: %w", err)
+	}
+	defer func() {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	info, err := packDir(ctx, wpmCli, cwd, wpmJson, opts.verbose, tempFile)
+	if err != nil {
+		return err
+	}
+	info.Name = wpmJson.Name
+	info.Version = wpmJson.Version
+	info.File = name
+
+	if err := tempFile.Chmod(0o644); err != nil {
+		return fmt.Errorf("failed to write tarball: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write tarball: %w", err)
+	}
+	tarballPath := filepath.Join(opts.out, name)
+	if err := os.Rename(tempFile.Name(), tarballPath); err != nil {
+		return fmt.Errorf("failed to write tarball: %w", err)
+	}
+
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(sidecarPath(tarballPath), append(data, '\n'), 0o644); err != nil { //nolint:gosec // the sidecar is as public as the tarball
+		return fmt.Errorf("failed to write %s: %w", sidecarPath(tarballPath), err)
+	}
+
+	printSummary(wpmCli, []summaryField{
+		{"Files", strconv.FormatInt(info.TotalFiles, 10)},
+		{"Size", sizeSummary(wpmCli, info)},
+		{"Digest", strings.TrimPrefix(info.Digest, "sha256:")},
+	})
+	warnSecrets(wpmCli, info.secrets)
+
+	if command.OutputFormat(wpmCli) == cliflags.FormatJSON {
+		return command.PrintJSON(wpmCli.Out(), info)
+	}
+
+	_, _ = fmt.Fprintf(wpmCli.Err(), "%s wrote %s\n", aec.GreenF.Apply("✔"), tarballPath)
+	_, _ = fmt.Fprintln(wpmCli.Out(), tarballPath)
+
+	return nil
+}
+
+// listPacked prints the files pack would put in the tarball, selected by the
+// files allowlist and .wpmignore the same way, without writing the tarball
+// anywhere.
+func listPacked(ctx context.Context, wpmCli command.Cli, dir string, wpmJson *wpmjson.Config) error {
+	var (
+		files   []string
+		secrets []string
+		size    int64
+	)
+	tarballer, err := pack(ctx, dir, wpmJson, wpmCli.Output(), func(name string, fi os.FileInfo) {
+		files = append(files, name)
+		size += fi.Size()
+		if wpmignore.LooksLikeSecret(name) {
+			secrets = append(secrets, name)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pack the package into a tarball: %w", err)
+	}
+	defer func() { _ = tarballer.Close() }()
+
+	// The reporter runs as the tarball is read, so drain it.
+	if _, err := io.Copy(io.Discard, tarballer.Reader()); err != nil {
+		return fmt.Errorf("failed to process tarball: %w", err)
+	}
+	slices.Sort(files)
+	slices.Sort(secrets)
+
+	switch command.OutputFormat(wpmCli) {
+	case cliflags.FormatJSON:
+		if files == nil {
+			files = []string{}
+		}
+		return command.PrintJSON(wpmCli.Out(), files)
+	default:
+		for _, f := range files {
+			_, _ = fmt.Fprintln(wpmCli.Out(), f)
+		}
+		_, _ = fmt.Fprintf(wpmCli.Err(), "\n%d %s, %s unpacked\n",
+			len(files), command.Pluralize("file", "s", len(files)), units.HumanSize(float64(size)))
+	}
+	if len(secrets) > 0 {
+		_, _ = fmt.Fprintln(wpmCli.Err())
+		warnSecrets(wpmCli, secrets)
+	}
+	return nil
+}
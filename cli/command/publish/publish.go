@@ -6,12 +6,14 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"hash"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/docker/go-units"
 	"github.com/morikuni/aec"
@@ -24,6 +26,7 @@ import (
 	"go.wpm.so/cli/pkg/archive"
 	"go.wpm.so/cli/pkg/output"
 	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/signatures"
 	"go.wpm.so/cli/pkg/pm/wpmignore"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
 	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
@@ -36,20 +39,30 @@ const (
 )
 
 type publishOptions struct {
-	dryRun  bool
-	verbose bool
-	tag     string
-	access  string
+	dryRun             bool
+	verbose            bool
+	tag                string
+	access             string
+	sign               bool
+	signKey            string
+	signer             string
+	verifyReproducible bool
 }
 
 func NewPublishCommand(wpmCli command.Cli) *cobra.Command {
 	var opts publishOptions
 
 	cmd := &cobra.Command{
-		Use:   "publish [OPTIONS]",
+		Use:   "publish [OPTIONS] [TARBALL]",
 		Short: "Publish a package to the wpm registry",
-		Args:  cli.NoArgs,
-		RunE:  func(cmd *cobra.Command, args []string) error { return runPublish(cmd.Context(), wpmCli, opts) },
+		Args:  cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var tarballPath string
+			if len(args) == 1 {
+				tarballPath = args[0]
+			}
+			return runPublish(cmd.Context(), wpmCli, opts, tarballPath)
+		},
 	}
 
 	flags := cmd.Flags()
@@ -58,6 +71,11 @@ func NewPublishCommand(wpmCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.verbose, "verbose", false, "Enable verbose output")
 	flags.StringVarP(&opts.access, "access", "a", "private", "Set the package access level to either public or private")
 	flags.BoolVar(&opts.dryRun, "dry-run", false, "Perform a publish operation without actually publishing the package")
+	flags.BoolVar(&opts.sign, "sign", false, "Sign the package as its author")
+	flags.StringVar(&opts.signKey, "sign-key", "", "Sign with the ECDSA P-256 private key in this PEM file (implies --sign)")
+	flags.StringVar(&opts.signer, "signer", "", "Sign by running this command, which reads the payload on stdin (implies --sign)")
+	flags.BoolVar(&opts.verifyReproducible, "verify-reproducible", false, "Rebuild the tarball from the current directory and check that it has the same digest")
+	cmd.MarkFlagsMutuallyExclusive("sign-key", "signer")
 
 	_ = cmd.RegisterFlagCompletionFunc("tag", completion.DistTags())
 	_ = cmd.RegisterFlagCompletionFunc("access", completion.PackageVisibility())
@@ -65,29 +83,47 @@ func NewPublishCommand(wpmCli command.Cli) *cobra.Command {
 	return cmd
 }
 
-func pack(ctx context.Context, path string, opts publishOptions, out *output.Output) (*archive.Tarballer, error) {
+// pack starts packing the package in path, calling report, if set, for every
+// file it adds.
+func pack(ctx context.Context, path string, wpmJson *wpmjson.Config, out *output.Output, report func(name string, fi os.FileInfo)) (*archive.Tarballer, error) {
 	ignorePatterns, err := wpmignore.ReadWpmIgnore(path)
 	if err != nil {
 		return nil, err
 	}
+	// Leave out install staging and earlier wpm pack output too, along with
+	// the temporary file wpm pack is writing to. These go first so that
+	// .wpmignore can still override them.
+	packed := tarballName(wpmJson.Name, "[0-9]*")
+	ignorePatterns = append([]string{"**/" + packed, "**/" + sidecarPath(packed), "**/." + packed + "-*"}, ignorePatterns...)
+	if contentDir := wpmJson.ContentDir(); contentDir != "" {
+		ignorePatterns = append([]string{filepath.ToSlash(filepath.Join(contentDir, ".tmp"))}, ignorePatterns...)
+	}
+
+	modTime, err := sourceDateEpoch()
+	if err != nil {
+		return nil, err
+	}
+
+	include, err := includeFiles(path, wpmJson, ignorePatterns, func(msg string) {
+		out.PrettyErrorln(output.Text{
+			Plain: "warn: " + msg,
+			Fancy: aec.YellowF.Apply("warn:") + " " + msg,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	tarOptions := &archive.TarOptions{
+		IncludeFiles:    include,
 		ExcludePatterns: ignorePatterns,
+		ModTime:         modTime,
 		Logger: func(format string, args ...any) {
 			out.ErrorWrite(fmt.Sprintf(format+"\n", args...))
 		},
 	}
 
-	tar, err := archive.Tar(ctx, path, tarOptions, func(fileInfo os.FileInfo) {
-		if opts.verbose {
-			sizeString := units.HumanSize(float64(fileInfo.Size()))
-			sizeString = fmt.Sprintf("%-7s", sizeString) // pad to 7 spaces since size string is capped to 4 numbers
-			out.PrettyErrorln(output.Text{
-				Plain: fmt.Sprintf("%s %s %s", "packed", sizeString, fileInfo.Name()),
-				Fancy: fmt.Sprintf("%s %s %s", aec.CyanF.Apply("packed"), sizeString, fileInfo.Name()),
-			})
-		}
-	})
+	tar, err := archive.Tar(ctx, path, tarOptions, report)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +131,32 @@ func pack(ctx context.Context, path string, opts publishOptions, out *output.Out
 	return tar, nil
 }
 
+// sourceDateEpoch returns the time set by SOURCE_DATE_EPOCH, or the zero time
+// if it isn't set. See https://reproducible-builds.org/specs/source-date-epoch/.
+func sourceDateEpoch() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: expected a Unix timestamp", epoch)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// reportPacked prints every file as it's packed, for --verbose.
+func reportPacked(out *output.Output) func(name string, fi os.FileInfo) {
+	return func(name string, fileInfo os.FileInfo) {
+		sizeString := units.HumanSize(float64(fileInfo.Size()))
+		sizeString = fmt.Sprintf("%-7s", sizeString) // pad to 7 spaces since size string is capped to 4 numbers
+		out.PrettyErrorln(output.Text{
+			Plain: fmt.Sprintf("%s %s %s", "packed", sizeString, name),
+			Fancy: fmt.Sprintf("%s %s %s", aec.CyanF.Apply("packed"), sizeString, name),
+		})
+	}
+}
+
 func getReadme(dirPath string) (string, error) {
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
@@ -144,55 +206,97 @@ func (c *tarballSizeCounter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func runPublish(ctx context.Context, wpmCli command.Cli, opts publishOptions) error {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
-	}
-
+func runPublish(ctx context.Context, wpmCli command.Cli, opts publishOptions, tarballPath string) error {
 	visibility := types.PackageVisibility(opts.access)
 	if !visibility.Valid() {
 		return errors.New("access must be either public or private")
 	}
 
-	wpmJson, err := wpmjson.Read(cwd)
+	// Set up the signer before packing, so a bad key fails fast.
+	signer, err := newSigner(opts)
 	if err != nil {
 		return err
 	}
-	if err := validateWpmJson(wpmJson); err != nil {
-		return err
-	}
-
-	_, _ = fmt.Fprintf(wpmCli.Err(), "📦 %s@%s\n\n", wpmJson.Name, wpmJson.Version)
 
-	tempFile, err := os.CreateTemp("", "wpm-tarball-*.tar.zst")
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to create temporary tarball: %w", err)
+		return fmt.Errorf("failed to get current working directory: %w", err)
 	}
-	defer func() {
-		_ = tempFile.Close()
-		_ = os.Remove(tempFile.Name())
-	}()
 
-	tarballer, err := pack(ctx, cwd, opts, wpmCli.Output())
-	if err != nil {
-		return fmt.Errorf("failed to pack the package into a tarball: %w", err)
-	}
-	defer func() { _ = tarballer.Close() }()
+	var (
+		wpmJson *wpmjson.Config
+		info    *tarball
+		readme  string
+		file    *os.File
+	)
+	if tarballPath != "" {
+		// Publish a tarball made by wpm pack exactly as it is, describing it
+		// by the wpm.json and readme inside it.
+		if file, err = os.Open(tarballPath); err != nil { //nolint:gosec // the user names the tarball to publish
+			return fmt.Errorf("failed to open tarball: %w", err)
+		}
+		defer func() { _ = file.Close() }()
 
-	hasher := sha256.New()
-	counter := &tarballSizeCounter{limit: maxPackedSize}
+		if info, wpmJson, readme, err = readTarball(file); err != nil {
+			return fmt.Errorf("failed to read %s: %w", tarballPath, err)
+		}
+		if err := checkSidecar(tarballPath, info); err != nil {
+			return err
+		}
+		if err := validateWpmJson(wpmJson); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(wpmCli.Err(), "📦 %s@%s %s\n\n", wpmJson.Name, wpmJson.Version, aec.Faint.Apply("from "+tarballPath))
+	} else {
+		if wpmJson, err = wpmjson.Read(cwd); err != nil {
+			return err
+		}
+		if err := validateWpmJson(wpmJson); err != nil {
+			return err
+		}
 
-	if err = packIntoTarball(wpmCli, opts, tarballer, tempFile, hasher, counter); err != nil {
-		return err
+		_, _ = fmt.Fprintf(wpmCli.Err(), "📦 %s@%s\n\n", wpmJson.Name, wpmJson.Version)
+
+		if file, err = os.CreateTemp("", "wpm-tarball-*.tar.zst"); err != nil {
+			return fmt.Errorf("failed to create temporary tarball: %w", err)
+		}
+		defer func() {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}()
+
+		if info, err = packDir(ctx, wpmCli, cwd, wpmJson, opts.verbose, file); err != nil {
+			return err
+		}
+		if readme, err = getReadme(cwd); err != nil {
+			return fmt.Errorf("failed to read readme file: %w", err)
+		}
 	}
 
-	if counter.total == 0 {
-		return errors.New("tarball size is zero, cannot publish empty package")
+	printSummary(wpmCli, []summaryField{
+		{"Tag", opts.tag},
+		{"Access", opts.access},
+		{"Files", strconv.FormatInt(info.TotalFiles, 10)},
+		{"Size", sizeSummary(wpmCli, info)},
+		{"Digest", strings.TrimPrefix(info.Digest, "sha256:")},
+	})
+
+	warnSecrets(wpmCli, info.secrets)
+
+	if opts.verifyReproducible {
+		if err := verifyReproducible(ctx, wpmCli, cwd, wpmJson, info); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(wpmCli.Err(), "%s reproducible, a rebuild has the same digest\n\n", aec.GreenF.Apply("✔"))
 	}
 
-	digest := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
-	printPublishSummary(wpmCli, opts, counter.total, tarballer, digest)
+	pkgManifest := buildManifest(wpmJson, opts, visibility, info, readme)
+	if signer != nil {
+		if err := signatures.SignManifest(ctx, pkgManifest, signer); err != nil {
+			return fmt.Errorf("failed to sign the package: %w", err)
+		}
+		_, _ = fmt.Fprintf(wpmCli.Err(), "%s signed with %s\n\n", aec.GreenF.Apply("✔"), output.Sanitize(pkgManifest.Dist.AuthorSignature.KeyID))
+	}
 
 	if opts.dryRun {
 		_, _ = fmt.Fprintf(wpmCli.Err(), "dry run complete, %s@%s is ready to be published\n", wpmJson.Name, wpmJson.Version)
@@ -208,13 +312,7 @@ func runPublish(ctx context.Context, wpmCli command.Cli, opts publishOptions) er
 		return err
 	}
 
-	readme, err := getReadme(cwd)
-	if err != nil {
-		return fmt.Errorf("failed to read readme file: %w", err)
-	}
-
-	pkgManifest := buildManifest(wpmJson, opts, visibility, digest, counter.total, tarballer, readme)
-	if err = uploadPackage(ctx, wpmCli, registryClient, pkgManifest, tempFile); err != nil {
+	if err = uploadPackage(ctx, wpmCli, registryClient, pkgManifest, file); err != nil {
 		return err
 	}
 
@@ -223,6 +321,25 @@ func runPublish(ctx context.Context, wpmCli command.Cli, opts publishOptions) er
 	return nil
 }
 
+// newSigner returns the signer to sign the package with, or nil if it isn't
+// to be signed.
+func newSigner(opts publishOptions) (signatures.Signer, error) {
+	switch {
+	case opts.signer != "":
+		return signatures.CommandSigner(opts.signer)
+	case opts.signKey != "":
+		key, err := signatures.ReadSigningKey(opts.signKey)
+		if err != nil {
+			return nil, err
+		}
+		return signatures.KeySigner(key)
+	case opts.sign:
+		return nil, errors.New("--sign needs a key: pass --sign-key or --signer")
+	default:
+		return nil, nil
+	}
+}
+
 func validateWpmJson(wpmJson *wpmjson.Config) error {
 	if wpmJson == nil {
 		return errors.New("no wpm.json found in the current directory")
@@ -236,17 +353,131 @@ func validateWpmJson(wpmJson *wpmjson.Config) error {
 	return nil
 }
 
-func packIntoTarball(wpmCli command.Cli, opts publishOptions, tarballer *archive.Tarballer, tempFile io.Writer, hasher hash.Hash, counter *tarballSizeCounter) error {
-	multiWriter := io.MultiWriter(tempFile, hasher, counter)
+// packDir packs the package in dir into w, the way publish uploads it.
+func packDir(ctx context.Context, wpmCli command.Cli, dir string, wpmJson *wpmjson.Config, verbose bool, w io.Writer) (*tarball, error) {
+	var secrets []string
+	printVerbose := reportPacked(wpmCli.Output())
+	report := func(name string, fi os.FileInfo) {
+		if wpmignore.LooksLikeSecret(name) {
+			secrets = append(secrets, name)
+		}
+		if verbose {
+			printVerbose(name, fi)
+		}
+	}
+
+	tarballer, err := pack(ctx, dir, wpmJson, wpmCli.Output(), report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack the package into a tarball: %w", err)
+	}
+	defer func() { _ = tarballer.Close() }()
+
+	hasher := sha256.New()
+	counter := &tarballSizeCounter{limit: maxPackedSize}
+
+	if err := packIntoTarball(wpmCli, verbose, tarballer, io.MultiWriter(w, hasher, counter)); err != nil {
+		return nil, err
+	}
+
+	if counter.total == 0 {
+		return nil, errors.New("tarball size is zero, cannot publish empty package")
+	}
+
+	return &tarball{
+		Digest:       "sha256:" + base64.StdEncoding.EncodeToString(hasher.Sum(nil)),
+		TotalFiles:   tarballer.FileCount(),
+		PackedSize:   counter.total,
+		UnpackedSize: tarballer.UnpackedSize(),
+		secrets:      secrets,
+	}, nil
+}
+
+// warnSecrets warns about packed files that look like they hold credentials,
+// before anything is uploaded.
+func warnSecrets(wpmCli command.Cli, secrets []string) {
+	if len(secrets) == 0 {
+		return
+	}
+
+	msg, them := fmt.Sprintf("%d files look like secrets and will be published:", len(secrets)), "them"
+	if len(secrets) == 1 {
+		msg, them = "1 file looks like a secret and will be published:", "it"
+	}
+	wpmCli.Output().PrettyErrorln(output.Text{
+		Plain: "warn: " + msg,
+		Fancy: aec.YellowF.Apply("warn:") + " " + msg,
+	})
+	for _, name := range secrets {
+		_, _ = fmt.Fprintf(wpmCli.Err(), "  %s\n", name)
+	}
+	_, _ = fmt.Fprintf(wpmCli.Err(), "Add %s to .wpmignore if that's not intended.\n", them)
+	_, _ = fmt.Fprintln(wpmCli.Err())
+}
+
+// verifyReproducible checks that dir packs to the tarball described by want,
+// byte for byte. It walks dir again and copies the packed files into a fresh
+// tree, with other modification times and permissions, and packs that, so
+// the file metadata on disk can't carry over. The rebuild runs on the same
+// machine, though, and can't tell a tarball that only differs elsewhere.
+func verifyReproducible(ctx context.Context, wpmCli command.Cli, dir string, wpmJson *wpmjson.Config, want *tarball) error {
+	rebuildDir, err := os.MkdirTemp("", "wpm-rebuild-*")
+	if err != nil {
+		return fmt.Errorf("failed to create rebuild directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(rebuildDir) }()
+
+	tarballer, err := pack(ctx, dir, wpmJson, wpmCli.Output(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to pack the package into a tarball: %w", err)
+	}
+	defer func() { _ = tarballer.Close() }()
+	if err := archive.Untar(ctx, tarballer.Reader(), rebuildDir, &archive.TarOptions{}); err != nil {
+		return fmt.Errorf("failed to copy the packed files: %w", err)
+	}
+
+	// Tarball entries sit in a "package" directory.
+	copied := filepath.Join(rebuildDir, "package")
+	if err := restamp(copied, time.Unix(1, 0)); err != nil {
+		return fmt.Errorf("failed to copy the packed files: %w", err)
+	}
+
+	rebuilt, err := packDir(ctx, wpmCli, copied, wpmJson, false, io.Discard)
+	if err != nil {
+		return err
+	}
+	if rebuilt.Digest != want.Digest {
+		return fmt.Errorf("tarball is not reproducible: a rebuild from %s has digest %s, expected %s", dir, rebuilt.Digest, want.Digest)
+	}
+	return nil
+}
+
+// restamp sets the modification time of every file and directory under dir
+// to t, and makes them private to the owner.
+func restamp(dir string, t time.Time) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0o600)
+		if d.IsDir() {
+			mode = 0o700
+		}
+		if err := os.Chmod(p, mode); err != nil {
+			return err
+		}
+		return os.Chtimes(p, t, t)
+	})
+}
 
+func packIntoTarball(wpmCli command.Cli, verbose bool, tarballer *archive.Tarballer, w io.Writer) error {
 	packFn := func() error {
-		if _, err := io.Copy(multiWriter, tarballer.Reader()); err != nil {
+		if _, err := io.Copy(w, tarballer.Reader()); err != nil {
 			return fmt.Errorf("failed to process tarball: %w", err)
 		}
 		return nil
 	}
 
-	if opts.verbose {
+	if verbose {
 		if err := packFn(); err != nil {
 			return err
 		}
@@ -257,7 +488,14 @@ func packIntoTarball(wpmCli command.Cli, opts publishOptions, tarballer *archive
 	return wpmCli.Progress().RunWithProgress("packing tarball", packFn, wpmCli.Err())
 }
 
-func printPublishSummary(wpmCli command.Cli, opts publishOptions, packedBytes int64, tarballer *archive.Tarballer, digest string) {
+// summaryField is a line of the summary printed before publishing or after
+// packing.
+type summaryField struct {
+	label string
+	value string
+}
+
+func printSummary(wpmCli command.Cli, fields []summaryField) {
 	c := func(a aec.ANSI, s string) string {
 		if !wpmCli.Err().IsColorEnabled() {
 			return s
@@ -266,19 +504,27 @@ func printPublishSummary(wpmCli command.Cli, opts publishOptions, packedBytes in
 	}
 	w := tabwriter.NewWriter(wpmCli.Err(), 0, 0, 2, ' ', 0)
 
-	packedSize := units.HumanSize(float64(packedBytes))
-	unpackedSize := units.HumanSize(float64(tarballer.UnpackedSize()))
-
-	_, _ = fmt.Fprintf(w, "├─ %s:\t%s\n", c(aec.LightBlueF, "Tag"), opts.tag)
-	_, _ = fmt.Fprintf(w, "├─ %s:\t%s\n", c(aec.LightBlueF, "Access"), opts.access)
-	_, _ = fmt.Fprintf(w, "├─ %s:\t%d\n", c(aec.LightBlueF, "Files"), tarballer.FileCount())
-	_, _ = fmt.Fprintf(w, "├─ %s:\t%s %s\n", c(aec.LightBlueF, "Size"), packedSize, c(aec.Faint, fmt.Sprintf("(%s unpacked)", unpackedSize)))
-	_, _ = fmt.Fprintf(w, "└─ %s:\t%s\n", c(aec.LightBlueF, "Digest"), digest)
+	for i, f := range fields {
+		branch := "├─"
+		if i == len(fields)-1 {
+			branch = "└─"
+		}
+		_, _ = fmt.Fprintf(w, "%s %s:\t%s\n", branch, c(aec.LightBlueF, f.label), f.value)
+	}
 
 	_ = w.Flush()
 	_, _ = fmt.Fprint(wpmCli.Err(), "\n")
 }
 
+// sizeSummary formats the packed and unpacked size of a tarball.
+func sizeSummary(wpmCli command.Cli, info *tarball) string {
+	unpacked := fmt.Sprintf("(%s unpacked)", units.HumanSize(float64(info.UnpackedSize)))
+	if wpmCli.Err().IsColorEnabled() {
+		unpacked = aec.Faint.Apply(unpacked)
+	}
+	return units.HumanSize(float64(info.PackedSize)) + " " + unpacked
+}
+
 func validateAuth(wpmCli command.Cli) error {
 	cfg := wpmCli.ConfigFile()
 	if cfg.DefaultUser == "" || cfg.AuthToken == "" {
@@ -287,7 +533,7 @@ func validateAuth(wpmCli command.Cli) error {
 	return nil
 }
 
-func buildManifest(wpmJson *wpmjson.Config, opts publishOptions, visibility types.PackageVisibility, digest string, packedBytes int64, tarballer *archive.Tarballer, readme string) *manifest.Package {
+func buildManifest(wpmJson *wpmjson.Config, opts publishOptions, visibility types.PackageVisibility, info *tarball, readme string) *manifest.Package {
 	return &manifest.Package{
 		Name:            wpmJson.Name,
 		Description:     wpmJson.Description,
@@ -302,10 +548,10 @@ func buildManifest(wpmJson *wpmjson.Config, opts publishOptions, visibility type
 		DevDependencies: wpmJson.DevDependencies,
 		Tag:             opts.tag,
 		Dist: manifest.Dist{
-			Digest:       "sha256:" + digest,
-			PackedSize:   packedBytes,
-			TotalFiles:   tarballer.FileCount(),
-			UnpackedSize: tarballer.UnpackedSize(),
+			Digest:       info.Digest,
+			PackedSize:   info.PackedSize,
+			TotalFiles:   info.TotalFiles,
+			UnpackedSize: info.UnpackedSize,
 		},
 		Wpm:        version.Version,
 		Visibility: visibility,
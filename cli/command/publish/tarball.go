@@ -0,0 +1,150 @@
+package publish
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"go.wpm.so/cli/pkg/archive"
+	"go.wpm.so/cli/pkg/pm/wpmignore"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+)
+
+// maxWpmJsonSize caps how much of a tarball's wpm.json is read.
+const maxWpmJsonSize = 1024 * 1024 // 1MB
+
+// tarball describes a packed package. It is also the JSON sidecar wpm pack
+// writes next to the tarball.
+type tarball struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	File         string `json:"file"`
+	Digest       string `json:"digest"`
+	TotalFiles   int64  `json:"totalFiles"`
+	PackedSize   int64  `json:"packedSize"`
+	UnpackedSize int64  `json:"unpackedSize"`
+
+	secrets []string // files that look like they hold credentials
+}
+
+// tarballName returns the file name wpm pack gives a package's tarball.
+func tarballName(name, version string) string {
+	return name + "-" + version + ".tar.zst"
+}
+
+// sidecarPath returns the path of the JSON sidecar for the tarball at path.
+func sidecarPath(tarballPath string) string {
+	return strings.TrimSuffix(tarballPath, ".tar.zst") + ".json"
+}
+
+// readTarball reads a tarball made by wpm pack, returning its description
+// along with the wpm.json and readme packed in it.
+func readTarball(r io.Reader) (*tarball, *wpmjson.Config, string, error) {
+	hasher := sha256.New()
+	counter := &tarballSizeCounter{limit: maxPackedSize}
+	raw := io.TeeReader(r, io.MultiWriter(hasher, counter))
+
+	stream, err := archive.DecompressStream(raw)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer func() { _ = stream.Close() }()
+
+	var (
+		info    tarball
+		wpmJson *wpmjson.Config
+		readme  string
+	)
+	tr := tar.NewReader(stream)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		name, ok := strings.CutPrefix(path.Clean(hdr.Name), "package/")
+		if !ok {
+			if path.Clean(hdr.Name) == "package" && hdr.Typeflag == tar.TypeDir {
+				continue
+			}
+			return nil, nil, "", fmt.Errorf("unexpected entry %q outside package/, not a wpm package tarball", hdr.Name)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		info.TotalFiles++
+		info.UnpackedSize += hdr.Size
+		if wpmignore.LooksLikeSecret(name) {
+			info.secrets = append(info.secrets, name)
+		}
+
+		switch {
+		case name == wpmjson.ConfigFile:
+			data, err := io.ReadAll(io.LimitReader(tr, maxWpmJsonSize))
+			if err != nil {
+				return nil, nil, "", err
+			}
+			if wpmJson, err = wpmjson.Parse(data); err != nil {
+				return nil, nil, "", err
+			}
+		case strings.EqualFold(name, "readme.md"):
+			data, err := io.ReadAll(io.LimitReader(tr, maxReadmeSize))
+			if err != nil {
+				return nil, nil, "", err
+			}
+			readme = string(data)
+		}
+	}
+	if wpmJson == nil {
+		return nil, nil, "", fmt.Errorf("no %s in the tarball", wpmjson.ConfigFile)
+	}
+
+	// Hash whatever follows the end of the tar stream too, so the digest
+	// covers every byte that is uploaded.
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return nil, nil, "", err
+	}
+	if _, err := io.Copy(io.Discard, raw); err != nil {
+		return nil, nil, "", err
+	}
+
+	info.Name = wpmJson.Name
+	info.Version = wpmJson.Version
+	info.Digest = "sha256:" + base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+	info.PackedSize = counter.total
+	return &info, wpmJson, readme, nil
+}
+
+// checkSidecar compares the tarball at path with the sidecar wpm pack wrote
+// for it, if there is one.
+func checkSidecar(tarballPath string, info *tarball) error {
+	p := sidecarPath(tarballPath)
+	data, err := os.ReadFile(p) //nolint:gosec // next to the tarball the user asked to publish
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var sidecar tarball
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	if sidecar.Digest != info.Digest {
+		return fmt.Errorf("%s doesn't match %s: digest is %s, expected %s", tarballPath, p, info.Digest, sidecar.Digest)
+	}
+	return nil
+}
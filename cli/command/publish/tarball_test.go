@@ -0,0 +1,65 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.wpm.so/cli/pkg/archive"
+)
+
+func TestReadTarball(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"wpm.json":       `{"name": "hello-dolly", "version": "1.7.2", "type": "plugin"}`,
+		"README.md":      "# Hello Dolly",
+		"hello.php":      "<?php",
+		"docs/faq.md":    "# FAQ",
+		"docs/readme.md": "not the package readme",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tarballer, err := archive.Tar(context.Background(), dir, &archive.TarOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(tarballer.Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, wpmJson, readme, err := readTarball(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(data)
+	if want := "sha256:" + base64.StdEncoding.EncodeToString(sum[:]); info.Digest != want {
+		t.Errorf("Digest = %s, want %s", info.Digest, want)
+	}
+	if info.PackedSize != int64(len(data)) {
+		t.Errorf("PackedSize = %d, want %d", info.PackedSize, len(data))
+	}
+	if info.TotalFiles != tarballer.FileCount() || info.UnpackedSize != tarballer.UnpackedSize() {
+		t.Errorf("TotalFiles, UnpackedSize = %d, %d; want %d, %d", info.TotalFiles, info.UnpackedSize, tarballer.FileCount(), tarballer.UnpackedSize())
+	}
+	if wpmJson.Name != "hello-dolly" || info.Version != "1.7.2" {
+		t.Errorf("read %s@%s, want hello-dolly@1.7.2", wpmJson.Name, info.Version)
+	}
+	if readme != "# Hello Dolly" {
+		t.Errorf("readme = %q", readme)
+	}
+}
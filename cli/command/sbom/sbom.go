@@ -0,0 +1,137 @@
+package sbom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/version"
+	"go.wpm.so/cli/pkg/pm/sbom"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+const (
+	formatCycloneDX = "cyclonedx-json"
+	formatSPDX      = "spdx-json"
+)
+
+type sbomOptions struct {
+	format  string
+	noDev   bool
+	runtime bool
+}
+
+func NewSbomCommand(wpmCli command.Cli) *cobra.Command {
+	var opts sbomOptions
+
+	cmd := &cobra.Command{
+		Use:   "sbom [OPTIONS]",
+		Short: "Print a software bill of materials for the project",
+		Args:  cli.NoArgs,
+		Example: `  wpm sbom > sbom.cdx.json
+  wpm sbom --format spdx-json --no-dev --runtime > sbom.spdx.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSbom(cmd.Context(), wpmCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	// This --format shadows the global one, which has no meaning for an SBOM.
+	flags.StringVar(&opts.format, "format", formatCycloneDX, `SBOM format ("cyclonedx-json", "spdx-json")`)
+	flags.BoolVar(&opts.noDev, "no-dev", false, "Leave out packages only needed by dev dependencies")
+	flags.BoolVar(&opts.runtime, "runtime", false, "Include WordPress and PHP from config.runtime as components")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{formatCycloneDX, formatSPDX}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func runSbom(ctx context.Context, wpmCli command.Cli, opts sbomOptions) error {
+	if opts.format != formatCycloneDX && opts.format != formatSPDX {
+		return fmt.Errorf("unsupported format %q, expected %s or %s", opts.format, formatCycloneDX, formatSPDX)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	config, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return errors.New("no wpm.json found, so nothing to describe")
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return errors.New("no wpm.lock found, run 'wpm install' first")
+	}
+
+	name := config.Name
+	if name == "" {
+		name = wpmjson.ProjectName(filepath.Base(cwd))
+	}
+
+	doc := sbom.New(name, config, lock, sbom.Options{NoDev: opts.noDev, Runtime: opts.runtime})
+	doc.Tool = version.Version
+
+	if err := fillLicenses(ctx, wpmCli, doc); err != nil {
+		return err
+	}
+
+	if opts.format == formatSPDX {
+		return sbom.WriteSPDX(wpmCli.Out(), doc)
+	}
+	return sbom.WriteCycloneDX(wpmCli.Out(), doc)
+}
+
+// fillLicenses sets the license of each package the lockfile has none for
+// from its manifest in the registry.
+func fillLicenses(ctx context.Context, wpmCli command.Cli, doc *sbom.Document) error {
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return err
+	}
+
+	progress := wpmCli.Progress()
+	progress.StartProgressIndicator(wpmCli.Err())
+	defer func() {
+		progress.Stream(wpmCli.Err(), "")
+		progress.StopProgressIndicator()
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(16)
+
+	for i := range doc.Components {
+		c := &doc.Components[i]
+		if c.License != "" || c.Kind == sbom.KindWordPress || c.Kind == sbom.KindPHP {
+			continue
+		}
+		progress.Stream(wpmCli.Err(), fmt.Sprintf("  Resolving %s@%s [%d/%d]", c.Name, c.Version, i+1, len(doc.Components)))
+
+		g.Go(func() error {
+			manifest, err := client.GetPackageManifest(ctx, c.Name, c.Version, false)
+			if err != nil {
+				return fmt.Errorf("failed to fetch package %s@%s: %w", c.Name, c.Version, err)
+			}
+			c.License = manifest.License
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
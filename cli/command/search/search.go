@@ -0,0 +1,233 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+const (
+	defaultLimit      = 20
+	maxLimit          = 100
+	descriptionLength = 60
+)
+
+type searchOptions struct {
+	pkgType     string
+	tag         string
+	requiresWP  string
+	requiresPHP string
+	license     string
+	sort        string
+	page        int
+	limit       int
+	json        bool
+}
+
+func NewSearchCommand(wpmCli command.Cli) *cobra.Command {
+	var opts searchOptions
+
+	cmd := &cobra.Command{
+		Use:   "search [OPTIONS] TERM...",
+		Short: "Search the registry for packages",
+		Args:  cli.RequiresMinArgs(1),
+		Example: `  wpm search contact form
+  wpm search --type theme --tag block-patterns
+  wpm search --requires-php 7.4 --sort downloads seo
+  wpm search --page 2 cache`,
+		Aliases: []string{"s"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSearch(cmd.Context(), wpmCli, opts, strings.Join(args, " "))
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.pkgType, "type", "", `Only show packages of this type ("plugin", "theme")`)
+	flags.StringVar(&opts.tag, "tag", "", "Only show packages with this tag")
+	flags.StringVar(&opts.requiresWP, "requires-wp", "", "Only show packages that support this WordPress version")
+	flags.StringVar(&opts.requiresPHP, "requires-php", "", "Only show packages that support this PHP version")
+	flags.StringVar(&opts.license, "license", "", "Only show packages with this license")
+	flags.StringVar(&opts.sort, "sort", registry.SortRelevance, `Sort results by "relevance" or "downloads"`)
+	flags.IntVar(&opts.page, "page", 1, "Page of results to show")
+	flags.IntVar(&opts.limit, "limit", defaultLimit, "Number of results per page (max 100)")
+	flags.BoolVar(&opts.json, "json", false, "Print the results as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("type", completion.PackageTypes())
+	_ = cmd.RegisterFlagCompletionFunc("license", completion.PackageLicenses())
+	_ = cmd.RegisterFlagCompletionFunc("sort", completion.FromList(registry.SortRelevance, registry.SortDownloads))
+
+	return cmd
+}
+
+func runSearch(ctx context.Context, wpmCli command.Cli, opts searchOptions, text string) error {
+	if err := validateOptions(opts); err != nil {
+		return err
+	}
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return err
+	}
+
+	var result *registry.SearchResult
+	err = wpmCli.Progress().RunWithProgress("", func() error {
+		result, err = client.Search(ctx, registry.SearchOptions{
+			Text:        text,
+			Type:        opts.pkgType,
+			Tag:         opts.tag,
+			RequiresWP:  opts.requiresWP,
+			RequiresPHP: opts.requiresPHP,
+			License:     opts.license,
+			Sort:        opts.sort,
+			Page:        opts.page,
+			PerPage:     opts.limit,
+		})
+		return err
+	}, wpmCli.Err())
+	if err != nil {
+		return fmt.Errorf("failed to search the registry: %w", err)
+	}
+	if result.Packages == nil {
+		result.Packages = []registry.SearchPackage{}
+	}
+	if result.Page == 0 {
+		result.Page, result.PerPage = opts.page, opts.limit
+	}
+
+	output.SanitizeValue(result)
+
+	switch {
+	case opts.json || command.OutputFormat(wpmCli) == cliflags.FormatJSON:
+		return command.PrintJSON(wpmCli.Out(), result)
+	case command.OutputFormat(wpmCli) == cliflags.FormatPlain:
+		printPlain(wpmCli.Out(), result.Packages)
+		return nil
+	}
+
+	if len(result.Packages) == 0 {
+		wpmCli.Out().WriteString("No packages found.\n")
+		return nil
+	}
+
+	printTable(wpmCli.Out(), wpmCli.Out().IsColorEnabled(), result.Packages)
+	_, _ = fmt.Fprintf(wpmCli.Out(), "\n%s\n", pageSummary(result))
+	return nil
+}
+
+func validateOptions(opts searchOptions) error {
+	if opts.pkgType != "" && !types.PackageType(opts.pkgType).Valid() {
+		return fmt.Errorf("invalid type %q: must be plugin or theme", opts.pkgType)
+	}
+	if !slices.Contains([]string{registry.SortRelevance, registry.SortDownloads}, opts.sort) {
+		return fmt.Errorf("invalid sort %q: must be relevance or downloads", opts.sort)
+	}
+	if opts.page < 1 {
+		return errors.New("--page must be 1 or more")
+	}
+	if opts.limit < 1 || opts.limit > maxLimit {
+		return fmt.Errorf("--limit must be between 1 and %d", maxLimit)
+	}
+	return nil
+}
+
+func printTable(out io.Writer, colorize bool, packages []registry.SearchPackage) {
+	c := func(a aec.ANSI, s string) string {
+		if !colorize {
+			return s
+		}
+		return a.Apply(s)
+	}
+
+	header := []string{"NAME", "VERSION", "TYPE", "DOWNLOADS", "DESCRIPTION"}
+	rows := make([][]string, 0, len(packages))
+	for _, p := range packages {
+		rows = append(rows, []string{p.Name, p.Version, p.Type, formatCount(p.Downloads), truncate(p.Description, descriptionLength)})
+	}
+
+	// Pad before coloring, as escape codes would throw off the widths.
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	pad := func(row []string, i int) string {
+		if i == len(row)-1 {
+			return row[i]
+		}
+		return row[i] + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(row[i])+2)
+	}
+
+	var line strings.Builder
+	for i := range header {
+		line.WriteString(c(aec.Bold, pad(header, i)))
+	}
+	_, _ = fmt.Fprintln(out, line.String())
+
+	for _, row := range rows {
+		line.Reset()
+		line.WriteString(c(aec.GreenF, pad(row, 0)))
+		for i := 1; i < len(row); i++ {
+			line.WriteString(pad(row, i))
+		}
+		_, _ = fmt.Fprintln(out, line.String())
+	}
+}
+
+// printPlain prints one tab-separated line per package: name, version, type,
+// downloads and description.
+func printPlain(out io.Writer, packages []registry.SearchPackage) {
+	for _, p := range packages {
+		description := strings.Join(strings.Fields(p.Description), " ")
+		_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%d\t%s\n", p.Name, p.Version, p.Type, p.Downloads, description)
+	}
+}
+
+// pageSummary tells which results are shown and how to get the next page.
+func pageSummary(r *registry.SearchResult) string {
+	first := (r.Page-1)*r.PerPage + 1
+	last := first + len(r.Packages) - 1
+	summary := fmt.Sprintf("Showing %d-%d of %d %s.", first, last, r.Total, command.Pluralize("package", "s", r.Total))
+	if last < r.Total {
+		summary += fmt.Sprintf(" Use --page %d for more.", r.Page+1)
+	}
+	return summary
+}
+
+// formatCount shortens large counts, e.g. 12345 to 12.3k.
+func formatCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/registry"
+)
+
+func TestValidateOptions(t *testing.T) {
+	valid := searchOptions{sort: registry.SortRelevance, page: 1, limit: defaultLimit}
+
+	tests := []struct {
+		name    string
+		modify  func(*searchOptions)
+		wantErr bool
+	}{
+		{name: "defaults", modify: func(*searchOptions) {}},
+		{name: "theme", modify: func(o *searchOptions) { o.pkgType = "theme" }},
+		{name: "bad type", modify: func(o *searchOptions) { o.pkgType = "mu-plugin" }, wantErr: true},
+		{name: "downloads", modify: func(o *searchOptions) { o.sort = registry.SortDownloads }},
+		{name: "bad sort", modify: func(o *searchOptions) { o.sort = "stars" }, wantErr: true},
+		{name: "page 0", modify: func(o *searchOptions) { o.page = 0 }, wantErr: true},
+		{name: "limit too high", modify: func(o *searchOptions) { o.limit = maxLimit + 1 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		opts := valid
+		tt.modify(&opts)
+		if err := validateOptions(opts); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateOptions() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPageSummary(t *testing.T) {
+	tests := []struct {
+		result registry.SearchResult
+		want   string
+	}{
+		{
+			result: registry.SearchResult{Total: 45, Page: 1, PerPage: 20, Packages: make([]registry.SearchPackage, 20)},
+			want:   "Showing 1-20 of 45 packages. Use --page 2 for more.",
+		},
+		{
+			result: registry.SearchResult{Total: 45, Page: 3, PerPage: 20, Packages: make([]registry.SearchPackage, 5)},
+			want:   "Showing 41-45 of 45 packages.",
+		},
+		{
+			result: registry.SearchResult{Total: 1, Page: 1, PerPage: 20, Packages: make([]registry.SearchPackage, 1)},
+			want:   "Showing 1-1 of 1 package.",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := pageSummary(&tt.result); got != tt.want {
+			t.Errorf("pageSummary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("Spam\nprotection", 60); got != "Spam protection" {
+		t.Errorf("truncate() = %q", got)
+	}
+	if got := truncate("abcdefghij", 5); got != "abcd…" {
+		t.Errorf("truncate() = %q, want %q", got, "abcd…")
+	}
+}
@@ -47,7 +47,7 @@ func runUninstall(ctx context.Context, wpmCli command.Cli, packages []string) er
 		Fancy: aec.Bold.Apply("wpm uninstall") + " " + aec.LightBlackF.Apply("v"+version.Version),
 	})
 
-	contentDir := wpmjson.New().ContentDir()
+	contentDir := wpmjson.NewInDir(cwd).ContentDir()
 	if probe, _ := wpmjson.Read(cwd); probe != nil {
 		contentDir = probe.ContentDir()
 	}
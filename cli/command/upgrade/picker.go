@@ -0,0 +1,161 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/morikuni/aec"
+	"golang.org/x/sync/errgroup"
+
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/wp/parser"
+)
+
+const (
+	excerptLines   = 3
+	excerptWorkers = 16
+)
+
+var groupTitles = map[string]string{
+	"patch":   "Patch updates",
+	"minor":   "Minor updates",
+	"major":   "Major updates",
+	"unknown": "Other updates",
+}
+
+// pickCandidates shows the checklist and returns the candidates the user
+// selects.
+func pickCandidates(ctx context.Context, wpmCli command.Cli, candidates []candidate) ([]candidate, error) {
+	sortByGroup(candidates)
+	fetchExcerpts(ctx, wpmCli, candidates)
+
+	wpmCli.Out().WriteString("\n")
+	printChecklist(wpmCli.Out(), wpmCli.Out().IsColorEnabled(), candidates)
+
+	for {
+		input, err := command.PromptForInput(ctx, wpmCli.In(), wpmCli.Out(),
+			"\nPackages to upgrade (e.g. 1,3-4, patch, all; empty to cancel): ")
+		if err != nil {
+			return nil, err
+		}
+
+		indexes, err := parseSelection(input, candidates)
+		if err != nil {
+			_, _ = fmt.Fprintln(wpmCli.Err(), err)
+			continue
+		}
+
+		selected := make([]candidate, 0, len(indexes))
+		for _, i := range indexes {
+			selected = append(selected, candidates[i])
+		}
+		return selected, nil
+	}
+}
+
+// fetchExcerpts fills in the changelog excerpt of each candidate's target
+// version. Packages whose manifest can't be fetched are left without one.
+func fetchExcerpts(ctx context.Context, wpmCli command.Cli, candidates []candidate) {
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return
+	}
+
+	progress := wpmCli.Progress()
+	progress.StartProgressIndicator(wpmCli.Err())
+	defer func() {
+		progress.Stream(wpmCli.Err(), "")
+		progress.StopProgressIndicator()
+	}()
+
+	var g errgroup.Group
+	g.SetLimit(excerptWorkers)
+
+	for i := range candidates {
+		c := &candidates[i]
+		progress.Stream(wpmCli.Err(), fmt.Sprintf("  Fetching changelog %s@%s [%d/%d]", c.Name, c.target, i+1, len(candidates)))
+
+		g.Go(func() error {
+			manifest, err := client.GetPackageManifest(ctx, c.Name, c.target, false)
+			if err == nil && manifest.Readme != "" {
+				c.excerpt = changelogExcerpt(manifest.Readme, c.target)
+			}
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+// changelogExcerpt returns the first lines of the readme's changelog entry
+// for version v.
+func changelogExcerpt(readme, v string) string {
+	p := parser.NewReadmeParser()
+	p.Parse(readme)
+
+	for _, entry := range p.Changelog() {
+		if entry.Version != v {
+			continue
+		}
+
+		var lines []string
+		for line := range strings.SplitSeq(entry.Notes, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > excerptLines {
+			lines = append(lines[:excerptLines], "…")
+		}
+		return output.Sanitize(strings.Join(lines, "\n"))
+	}
+	return ""
+}
+
+// printChecklist lists candidates, numbered from 1, under a heading per
+// update type.
+func printChecklist(out io.Writer, colorize bool, candidates []candidate) {
+	c := func(a aec.ANSI, s string) string {
+		if !colorize {
+			return s
+		}
+		return a.Apply(s)
+	}
+
+	width := 0
+	for _, cand := range candidates {
+		width = max(width, len(cand.Name))
+	}
+
+	group := ""
+	for i, cand := range candidates {
+		if cand.diffType != group {
+			group = cand.diffType
+			if i > 0 {
+				_, _ = fmt.Fprintln(out)
+			}
+			_, _ = fmt.Fprintln(out, c(aec.Bold, groupTitles[group]))
+		}
+
+		line := fmt.Sprintf("%3d. %-*s  %s → %s", i+1, width, cand.Name, cand.Current, c(aec.GreenF, cand.target))
+		if cand.Dev {
+			line += " " + c(aec.Faint, "(dev)")
+		}
+		if cand.Incompatible != "" {
+			line += " " + c(aec.YellowF, "(latest "+cand.Latest+" doesn't support your runtime)")
+		}
+		if cand.TooNew != "" {
+			line += " " + c(aec.YellowF, "(latest "+cand.Latest+" is newer than the minimum release age)")
+		}
+		_, _ = fmt.Fprintln(out, line)
+
+		for excerptLine := range strings.SplitSeq(cand.excerpt, "\n") {
+			if excerptLine != "" {
+				_, _ = fmt.Fprintf(out, "     %s\n", c(aec.Faint, excerptLine))
+			}
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
+	"go.wpm.so/cli/cli/command/install"
+	"go.wpm.so/cli/cli/command/outdated"
+	"go.wpm.so/cli/cli/version"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/workspace"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+type upgradeOptions struct {
+	interactive bool
+	dryRun      bool
+}
+
+func NewUpgradeCommand(wpmCli command.Cli) *cobra.Command {
+	var opts upgradeOptions
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [OPTIONS] [PACKAGE]...",
+		Short: "Upgrade dependencies to their newest compatible versions",
+		Args:  cobra.ArbitraryArgs,
+		Example: `  wpm upgrade
+  wpm upgrade akismet
+  wpm upgrade -i`,
+		Aliases: []string{"up"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpgrade(cmd.Context(), wpmCli, opts, args)
+		},
+		ValidArgsFunction: completion.PackagesFromWpmJson(),
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.interactive, "interactive", "i", false, "Pick the packages to upgrade from a checklist")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Do not write anything to disk")
+
+	return cmd
+}
+
+func runUpgrade(ctx context.Context, wpmCli command.Cli, opts upgradeOptions, packages []string) error {
+	if opts.interactive && !wpmCli.In().IsTerminal() {
+		return errors.New("wpm upgrade -i needs an interactive terminal; name the packages to upgrade instead, e.g. wpm upgrade akismet")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	wpmCli.Output().Prettyln(output.Text{
+		Plain: "wpm upgrade v" + version.Version,
+		Fancy: aec.Bold.Apply("wpm upgrade") + " " + aec.LightBlackF.Apply("v"+version.Version),
+	})
+
+	contentDir := wpmjson.NewInDir(cwd).ContentDir()
+	if probe, _ := wpmjson.Read(cwd); probe != nil {
+		contentDir = probe.ContentDir()
+	}
+
+	wsLock, err := workspace.AcquireLock(ctx, filepath.Join(cwd, contentDir), func() {
+		wpmCli.Output().PrettyErrorln(output.Text{
+			Plain: "waiting for another wpm process to finish in this workspace...",
+			Fancy: aec.Faint.Apply("waiting for another wpm process to finish in this workspace..."),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to acquire workspace lock: %w", err)
+	}
+	defer func() {
+		_ = wsLock.Release()
+	}()
+
+	cfg, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return errors.New("no wpm.json found, so nothing to upgrade")
+	}
+
+	lock, err := wpmlock.Read(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	if lock == nil {
+		return errors.New("no wpm.lock found, run 'wpm install' first to generate a lockfile")
+	}
+
+	for _, name := range packages {
+		if !isDependency(cfg, name) {
+			return fmt.Errorf("%s is not a dependency in wpm.json", name)
+		}
+	}
+
+	entries, err := outdated.Find(ctx, wpmCli, cfg, lock, outdated.FindOptions{})
+	if err != nil {
+		return err
+	}
+
+	candidates := upgradable(entries, packages)
+	if len(candidates) == 0 {
+		wpmCli.Out().WriteString("\nAlready up-to-date!\n")
+		return nil
+	}
+
+	if opts.interactive {
+		candidates, err = pickCandidates(ctx, wpmCli, candidates)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			wpmCli.Out().WriteString("No packages selected.\n")
+			return nil
+		}
+	}
+
+	for _, c := range candidates {
+		if c.Dev {
+			(*cfg.DevDependencies)[c.Name] = c.target
+		} else {
+			(*cfg.Dependencies)[c.Name] = c.target
+		}
+	}
+
+	return install.Run(ctx, cwd, wpmCli, install.RunOptions{
+		DryRun:     opts.dryRun,
+		Config:     cfg,
+		SaveConfig: !opts.dryRun,
+		Trigger:    install.TriggerUpdate,
+	})
+}
+
+func isDependency(cfg *wpmjson.Config, name string) bool {
+	if cfg.Dependencies != nil {
+		if _, ok := (*cfg.Dependencies)[name]; ok {
+			return true
+		}
+	}
+	if cfg.DevDependencies != nil {
+		if _, ok := (*cfg.DevDependencies)[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// candidate is an outdated dependency that can move to target, the newest
+// version that runs on the project's runtime.
+type candidate struct {
+	outdated.Entry
+	target   string
+	diffType string // from the current version to target
+	excerpt  string
+}
+
+// upgradable returns the entries that have a newer compatible version,
+// restricted to names when any are given.
+func upgradable(entries []outdated.Entry, names []string) []candidate {
+	var candidates []candidate
+	for _, e := range entries {
+		if len(names) > 0 && !slices.Contains(names, e.Name) {
+			continue
+		}
+		current, err1 := semver.NewVersion(e.Current)
+		wanted, err2 := semver.NewVersion(e.Wanted)
+		if err1 != nil || err2 != nil || !wanted.GreaterThan(current) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			Entry:    e,
+			target:   e.Wanted,
+			diffType: outdated.DiffType(e.Current, e.Wanted),
+		})
+	}
+	return candidates
+}
+
+// groupOrder is the order in which the checklist lists updates.
+var groupOrder = []string{"patch", "minor", "major", "unknown"}
+
+// sortByGroup orders candidates as they appear in the checklist.
+func sortByGroup(candidates []candidate) {
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return slices.Index(groupOrder, a.diffType) - slices.Index(groupOrder, b.diffType)
+	})
+}
+
+// parseSelection turns checklist input into candidate indexes. Items are
+// separated by commas or spaces and are 1-based numbers, ranges such as
+// "2-4", group names ("patch", "minor", "major") or "all". Empty input
+// selects nothing.
+func parseSelection(input string, candidates []candidate) ([]int, error) {
+	selected := make(map[int]bool)
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+
+	for _, field := range fields {
+		field = strings.ToLower(field)
+		switch {
+		case field == "all" || field == "a":
+			for i := range candidates {
+				selected[i] = true
+			}
+		case slices.Contains(groupOrder, field):
+			for i, c := range candidates {
+				if c.diffType == field {
+					selected[i] = true
+				}
+			}
+		default:
+			lo, hi, err := parseRange(field, len(candidates))
+			if err != nil {
+				return nil, err
+			}
+			for i := lo; i <= hi; i++ {
+				selected[i-1] = true
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(selected))
+	for i := range selected {
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+	return indexes, nil
+}
+
+func parseRange(field string, n int) (lo, hi int, err error) {
+	first, last, isRange := strings.Cut(field, "-")
+	if !isRange {
+		last = first
+	}
+	lo, err1 := strconv.Atoi(first)
+	hi, err2 := strconv.Atoi(last)
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("invalid selection %q", field)
+	}
+	if lo < 1 || hi > n || lo > hi {
+		return 0, 0, fmt.Errorf("selection %q is out of range 1-%d", field, n)
+	}
+	return lo, hi, nil
+}
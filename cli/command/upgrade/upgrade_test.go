@@ -0,0 +1,79 @@
+package upgrade
+
+import (
+	"slices"
+	"testing"
+
+	"go.wpm.so/cli/cli/command/outdated"
+)
+
+func TestUpgradable(t *testing.T) {
+	entries := []outdated.Entry{
+		{Name: "akismet", Current: "5.3.1", Wanted: "5.4.0", Latest: "5.4.0"},
+		{Name: "jetpack", Current: "13.0.0", Wanted: "13.0.0", Latest: "14.0.0"},
+		{Name: "woocommerce", Current: "8.9.3", Wanted: "", Latest: "9.4.0"},
+		{Name: "hello-dolly", Current: "1.7.2", Wanted: "1.7.3", Latest: "1.7.3"},
+	}
+
+	got := upgradable(entries, nil)
+	if len(got) != 2 || got[0].Name != "akismet" || got[0].diffType != "minor" || got[1].diffType != "patch" {
+		t.Errorf("upgradable() = %+v, want akismet (minor) and hello-dolly (patch)", got)
+	}
+
+	if got := upgradable(entries, []string{"hello-dolly"}); len(got) != 1 || got[0].target != "1.7.3" {
+		t.Errorf("upgradable(hello-dolly) = %+v", got)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	candidates := []candidate{{diffType: "patch"}, {diffType: "patch"}, {diffType: "minor"}, {diffType: "major"}}
+
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{input: "", want: []int{}},
+		{input: "1", want: []int{0}},
+		{input: "3, 1", want: []int{0, 2}},
+		{input: "2-4", want: []int{1, 2, 3}},
+		{input: "patch 4", want: []int{0, 1, 3}},
+		{input: "all", want: []int{0, 1, 2, 3}},
+		{input: "5", wantErr: true},
+		{input: "3-2", wantErr: true},
+		{input: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSelection(tt.input, candidates)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSelection(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !slices.Equal(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestChangelogExcerpt(t *testing.T) {
+	readme := `=== Akismet ===
+
+== Changelog ==
+
+= 5.4 =
+* One.
+* Two.
+
+* Three.
+* Four.
+
+= 5.3.1 =
+* Fix.
+`
+	if got, want := changelogExcerpt(readme, "5.4.0"), "* One.\n* Two.\n* Three.\n…"; got != want {
+		t.Errorf("changelogExcerpt() = %q, want %q", got, want)
+	}
+	if got := changelogExcerpt(readme, "6.0.0"); got != "" {
+		t.Errorf("changelogExcerpt() = %q, want none", got)
+	}
+}
@@ -0,0 +1,128 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// maxHeaderBytes is how far into a file WordPress looks for its headers.
+const maxHeaderBytes = 8 * 1024
+
+// bumpVersion returns the version after current that spec asks for: major,
+// minor, patch, prerelease, or an explicit version. Like npm, bumping a
+// pre-release to the release it leads up to drops the pre-release, so
+// "major" on 2.0.0-beta.1 gives 2.0.0. preid names new pre-releases, such as
+// "beta" for 1.2.4-beta.0.
+func bumpVersion(current, spec, preid string) (string, error) {
+	cur, err := semver.StrictNewVersion(current)
+	if err != nil {
+		return "", fmt.Errorf("current version %q is not a valid semantic version", current)
+	}
+	pre := cur.Prerelease() != ""
+
+	var next semver.Version
+	switch spec {
+	case "major":
+		if pre && cur.Minor() == 0 && cur.Patch() == 0 {
+			next = *semver.New(cur.Major(), 0, 0, "", "")
+		} else {
+			next = cur.IncMajor()
+		}
+	case "minor":
+		if pre && cur.Patch() == 0 {
+			next = *semver.New(cur.Major(), cur.Minor(), 0, "", "")
+		} else {
+			next = cur.IncMinor()
+		}
+	case "patch":
+		next = cur.IncPatch()
+	case "prerelease":
+		if pre {
+			next = *semver.New(cur.Major(), cur.Minor(), cur.Patch(), nextPrerelease(cur.Prerelease(), preid), "")
+		} else {
+			next = *semver.New(cur.Major(), cur.Minor(), cur.Patch()+1, nextPrerelease("", preid), "")
+		}
+	default:
+		v, err := semver.StrictNewVersion(spec)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %q: expected major, minor, patch, prerelease or a version such as 1.2.3", spec)
+		}
+		next = *v
+	}
+
+	if next.Equal(cur) {
+		return "", errors.New("version is already " + current)
+	}
+	return next.String(), nil
+}
+
+// nextPrerelease increments the last numeric identifier of pre, or starts
+// preid at 0 when pre is empty or a pre-release of another kind.
+func nextPrerelease(pre, preid string) string {
+	if pre != "" && (preid == "" || pre == preid || strings.HasPrefix(pre, preid+".")) {
+		ids := strings.Split(pre, ".")
+		if n, err := strconv.Atoi(ids[len(ids)-1]); err == nil {
+			ids[len(ids)-1] = strconv.Itoa(n + 1)
+			return strings.Join(ids, ".")
+		}
+		return pre + ".0"
+	}
+	if preid == "" {
+		return "0"
+	}
+	return preid + ".0"
+}
+
+// headerLine matches the line of a WordPress file header, the way
+// get_file_data() does, capturing what comes before and after the value.
+func headerLine(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?mi)^((?:[ \t]*<\?php)?[ \t/*#@]*` + regexp.QuoteMeta(name) + `:[ \t]*)(.*?)([ \t\r]*)$`)
+}
+
+// setHeader sets the value of the header name in the first maxHeaderBytes of
+// content, reporting whether there was such a header.
+func setHeader(content []byte, name, value string) ([]byte, bool) {
+	loc := headerLine(name).FindSubmatchIndex(content)
+	if loc == nil || loc[0] >= maxHeaderBytes {
+		return content, false
+	}
+
+	out := make([]byte, 0, len(content)+len(value))
+	out = append(out, content[:loc[4]]...)
+	out = append(out, value...)
+	out = append(out, content[loc[5]:]...)
+	return out, true
+}
+
+var changelogHeading = regexp.MustCompile(`(?mi)^==[ \t]*changelog[ \t]*==[ \t\r]*$`)
+
+// addChangelogEntry adds an empty entry for version at the top of the
+// Changelog section of a readme.txt, adding the section if there is none.
+func addChangelogEntry(readme []byte, version string) []byte {
+	newline := "\n"
+	if strings.Contains(string(readme), "\r\n") {
+		newline = "\r\n"
+	}
+	entry := "= " + version + " =" + newline + "* " + newline
+
+	loc := changelogHeading.FindIndex(readme)
+	if loc == nil {
+		out := strings.TrimRight(string(readme), "\r\n")
+		return []byte(out + newline + newline + "== Changelog ==" + newline + newline + entry)
+	}
+
+	// Skip the heading and the blank lines after it.
+	rest := string(readme[loc[1]:])
+	trimmed := strings.TrimLeft(rest, "\r\n")
+	head := string(readme[:loc[1]]) + newline + newline
+
+	if trimmed == "" {
+		return []byte(head + entry)
+	}
+	return []byte(head + entry + newline + trimmed)
+}
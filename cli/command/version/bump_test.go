@@ -0,0 +1,73 @@
+package version
+
+import "testing"
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		current, spec, preid string
+		want                 string
+		wantErr              bool
+	}{
+		{current: "1.2.3", spec: "major", want: "2.0.0"},
+		{current: "1.2.3", spec: "minor", want: "1.3.0"},
+		{current: "1.2.3", spec: "patch", want: "1.2.4"},
+		{current: "2.0.0-beta.1", spec: "major", want: "2.0.0"},
+		{current: "1.3.0-beta.1", spec: "minor", want: "1.3.0"},
+		{current: "1.2.4-rc.1", spec: "patch", want: "1.2.4"},
+		{current: "1.2.3", spec: "prerelease", want: "1.2.4-0"},
+		{current: "1.2.3", spec: "prerelease", preid: "beta", want: "1.2.4-beta.0"},
+		{current: "1.2.4-beta.0", spec: "prerelease", want: "1.2.4-beta.1"},
+		{current: "1.2.4-beta", spec: "prerelease", want: "1.2.4-beta.0"},
+		{current: "1.2.4-alpha.3", spec: "prerelease", preid: "beta", want: "1.2.4-beta.0"},
+		{current: "1.2.3", spec: "1.5.0", want: "1.5.0"},
+		{current: "1.2.3", spec: "1.2.3", wantErr: true},
+		{current: "1.2.3", spec: "1.5", wantErr: true},
+		{current: "1.2", spec: "patch", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := bumpVersion(tt.current, tt.spec, tt.preid)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("bumpVersion(%q, %q, %q) = %q, %v; want %q, error %v", tt.current, tt.spec, tt.preid, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	plugin := "<?php\r\n/**\r\n * Plugin Name: Hello Dolly\r\n * Version:     1.7.2\r\n */\r\ndefine( 'VERSION', '1.7.2' );\r\n"
+	got, ok := setHeader([]byte(plugin), "Version", "1.8.0")
+	want := "<?php\r\n/**\r\n * Plugin Name: Hello Dolly\r\n * Version:     1.8.0\r\n */\r\ndefine( 'VERSION', '1.7.2' );\r\n"
+	if !ok || string(got) != want {
+		t.Errorf("setHeader() = %q, %v; want %q", got, ok, want)
+	}
+
+	if _, ok := setHeader([]byte("<?php\n// Requires PHP: 7.4\n"), "Version", "1.8.0"); ok {
+		t.Error("setHeader() found a Version header that isn't there")
+	}
+}
+
+func TestAddChangelogEntry(t *testing.T) {
+	tests := []struct {
+		name, readme, want string
+	}{
+		{
+			name:   "existing section",
+			readme: "=== Hello ===\n\n== Changelog ==\n\n= 1.7.2 =\n* Fix.\n",
+			want:   "=== Hello ===\n\n== Changelog ==\n\n= 1.8.0 =\n* \n\n= 1.7.2 =\n* Fix.\n",
+		},
+		{
+			name:   "empty section",
+			readme: "=== Hello ===\n\n== Changelog ==\n",
+			want:   "=== Hello ===\n\n== Changelog ==\n\n= 1.8.0 =\n* \n",
+		},
+		{
+			name:   "no section",
+			readme: "=== Hello ===\n\nShort.\n",
+			want:   "=== Hello ===\n\nShort.\n\n== Changelog ==\n\n= 1.8.0 =\n* \n",
+		},
+	}
+	for _, tt := range tests {
+		if got := string(addChangelogEntry([]byte(tt.readme), "1.8.0")); got != tt.want {
+			t.Errorf("%s: addChangelogEntry() =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
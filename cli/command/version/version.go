@@ -0,0 +1,268 @@
+package version
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/cli/command/completion"
+	"go.wpm.so/cli/pkg/atomicwriter"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/wp/parser"
+)
+
+type versionOptions struct {
+	preid     string
+	changelog bool
+	git       bool
+}
+
+func NewVersionCommand(wpmCli command.Cli) *cobra.Command {
+	var opts versionOptions
+
+	cmd := &cobra.Command{
+		Use:               "version [OPTIONS] major|minor|patch|prerelease|VERSION",
+		Short:             "Bump the package version in wpm.json, the main file and readme.txt",
+		Args:              cli.ExactArgs(1),
+		ValidArgsFunction: completion.FromList("major", "minor", "patch", "prerelease"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVersion(cmd.Context(), wpmCli, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVar(&opts.preid, "preid", "", "Identifier for new pre-releases, such as \"beta\" for 1.2.4-beta.0")
+	flags.BoolVar(&opts.changelog, "changelog", false, "Add an entry for the new version to the changelog in readme.txt")
+	flags.BoolVar(&opts.git, "git", false, "Commit the changes and tag the commit as v<version>")
+
+	return cmd
+}
+
+// versionedFile is a file whose copy of the version is updated.
+type versionedFile struct {
+	name    string
+	changes []string // what was updated, such as "Version" for that header
+	content []byte
+	mode    os.FileMode
+}
+
+func runVersion(ctx context.Context, wpmCli command.Cli, spec string, opts versionOptions) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	wpmJson, err := wpmjson.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if wpmJson == nil {
+		return errors.New("no wpm.json found in the current directory")
+	}
+
+	next, err := bumpVersion(wpmJson.Version, spec, opts.preid)
+	if err != nil {
+		return err
+	}
+
+	if opts.git {
+		if err := checkGit(ctx, cwd, next); err != nil {
+			return err
+		}
+	}
+
+	// Work out every change before writing anything, so that an error leaves
+	// the package as it was.
+	var files []*versionedFile
+
+	main, err := mainFile(cwd, wpmJson.Type)
+	if err != nil {
+		return err
+	}
+	if main != nil {
+		if main.content, main.changes = updateHeader(main.content, "Version", next); len(main.changes) == 0 {
+			printWarning(wpmCli, main.name+" has no Version header, leaving it as it is")
+		} else {
+			files = append(files, main)
+		}
+	} else {
+		printWarning(wpmCli, fmt.Sprintf("no main %s file found, only wpm.json is updated", wpmJson.Type))
+	}
+
+	readme, err := readFile(cwd, "readme.txt")
+	if err != nil {
+		return err
+	}
+	switch {
+	case readme == nil && opts.changelog:
+		return errors.New("--changelog needs a readme.txt in the current directory")
+	case readme != nil:
+		// The stable tag is what WordPress.org serves, so leave it on the
+		// last release while publishing pre-releases.
+		if !strings.Contains(next, "-") {
+			readme.content, readme.changes = updateHeader(readme.content, "Stable tag", next)
+		}
+		if opts.changelog {
+			readme.content = addChangelogEntry(readme.content, next)
+			readme.changes = append(readme.changes, "changelog")
+		}
+		if len(readme.changes) > 0 {
+			files = append(files, readme)
+		}
+	}
+
+	previous := wpmJson.Version
+	wpmJson.Version = next
+	if err := wpmJson.Write(cwd); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := atomicwriter.WriteFile(filepath.Join(cwd, f.name), f.content, f.mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	wpmCli.Output().PrettyErrorln(output.Text{
+		Plain: fmt.Sprintf("%s %s → %s\n", wpmJson.Name, previous, next),
+		Fancy: fmt.Sprintf("%s %s → %s\n", wpmJson.Name, previous, aec.Bold.Apply(next)),
+	})
+	printUpdated(wpmCli, wpmjson.ConfigFile, nil)
+	for _, f := range files {
+		printUpdated(wpmCli, f.name, f.changes)
+	}
+
+	if opts.git {
+		names := []string{wpmjson.ConfigFile}
+		for _, f := range files {
+			names = append(names, f.name)
+		}
+		if err := commitAndTag(ctx, cwd, next, names); err != nil {
+			return err
+		}
+		printUpdated(wpmCli, "committed and tagged v"+next, nil)
+	}
+
+	_, _ = fmt.Fprintln(wpmCli.Out(), "v"+next)
+	return nil
+}
+
+// mainFile reads the file WordPress takes the package version from: the main
+// plugin file, found the way WordPress finds it, or style.css for themes. It
+// returns nil if there is none.
+func mainFile(dir string, pkgType types.PackageType) (*versionedFile, error) {
+	switch pkgType {
+	case types.TypeTheme:
+		return readFile(dir, "style.css")
+	case types.TypePlugin:
+		path, _, err := parser.FindMainPluginFile(dir)
+		if err != nil {
+			return nil, nil
+		}
+		return readFile(dir, filepath.Base(path))
+	}
+	return nil, nil
+}
+
+// readFile reads name in dir, returning nil if it doesn't exist.
+func readFile(dir, name string) (*versionedFile, error) {
+	path := filepath.Join(dir, name)
+	fi, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec // a file of the package in the current directory
+	if err != nil {
+		return nil, err
+	}
+	return &versionedFile{name: name, content: content, mode: fi.Mode().Perm()}, nil
+}
+
+// updateHeader sets the header name to value, returning the headers set.
+func updateHeader(content []byte, name, value string) ([]byte, []string) {
+	updated, ok := setHeader(content, name, value)
+	if !ok {
+		return content, nil
+	}
+	return updated, []string{name}
+}
+
+// checkGit makes sure the version can be committed and tagged: the working
+// tree has no uncommitted changes and the tag doesn't exist yet.
+func checkGit(ctx context.Context, dir, version string) error {
+	status, err := git(ctx, dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return err
+	}
+	if status != "" {
+		return errors.New("git working tree has uncommitted changes, commit or stash them before bumping the version")
+	}
+	if _, err := git(ctx, dir, "rev-parse", "--quiet", "--verify", "refs/tags/v"+version); err == nil {
+		return fmt.Errorf("git tag v%s already exists", version)
+	}
+	return nil
+}
+
+func commitAndTag(ctx context.Context, dir, version string, files []string) error {
+	if _, err := git(ctx, dir, append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+	if _, err := git(ctx, dir, "commit", "--message", version); err != nil {
+		return err
+	}
+	if _, err := git(ctx, dir, "tag", "--annotate", "v"+version, "--message", version); err != nil {
+		return err
+	}
+	return nil
+}
+
+// git runs git in dir and returns its trimmed output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func printUpdated(wpmCli command.Cli, name string, changes []string) {
+	detail := ""
+	if len(changes) > 0 {
+		detail = " (" + strings.Join(changes, ", ") + ")"
+	}
+	wpmCli.Output().PrettyErrorln(output.Text{
+		Plain: "✔ " + name + detail,
+		Fancy: aec.GreenF.Apply("✔") + " " + name + aec.Faint.Apply(detail),
+	})
+}
+
+func printWarning(wpmCli command.Cli, msg string) {
+	wpmCli.Output().PrettyErrorln(output.Text{
+		Plain: "warn: " + msg,
+		Fancy: aec.YellowF.Apply("warn:") + " " + msg,
+	})
+}
@@ -0,0 +1,77 @@
+package view
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/morikuni/aec"
+)
+
+var (
+	headingRegex = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listRegex    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	ruleRegex    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	imageRegex   = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	linkRegex    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	boldRegex    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	codeRegex    = regexp.MustCompile("`([^`]+)`")
+	commentRegex = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// renderReadme prints a Markdown readme for the terminal. Without color the
+// Markdown is printed as is, since it reads well enough on its own.
+func renderReadme(out io.Writer, colorize bool, readme string) {
+	readme = strings.ReplaceAll(readme, "\r\n", "\n")
+	if !colorize {
+		_, _ = fmt.Fprintln(out, strings.TrimSpace(readme))
+		return
+	}
+
+	readme = commentRegex.ReplaceAllString(readme, "")
+
+	inFence := false
+	for line := range strings.SplitSeq(strings.TrimSpace(readme), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			_, _ = fmt.Fprintln(out, "    "+aec.Faint.Apply(line))
+			continue
+		}
+		_, _ = fmt.Fprintln(out, renderLine(line))
+	}
+}
+
+// renderLine renders one line of Markdown outside of code blocks.
+func renderLine(line string) string {
+	if m := headingRegex.FindStringSubmatch(line); m != nil {
+		if len(m[1]) == 1 {
+			return aec.Bold.With(aec.Underline).Apply(m[2])
+		}
+		return aec.Bold.Apply(m[2])
+	}
+	if ruleRegex.MatchString(line) {
+		return aec.Faint.Apply(strings.Repeat("─", 40))
+	}
+	if m := listRegex.FindStringSubmatch(line); m != nil {
+		return m[1] + "• " + renderInline(m[2])
+	}
+	if rest, ok := strings.CutPrefix(line, ">"); ok {
+		return aec.Faint.Apply("│") + " " + renderInline(strings.TrimPrefix(rest, " "))
+	}
+	return renderInline(line)
+}
+
+// renderInline styles emphasis, code spans and links within a line.
+func renderInline(s string) string {
+	s = imageRegex.ReplaceAllString(s, aec.Faint.Apply("[image: $1]"))
+	s = linkRegex.ReplaceAllString(s, "$1 "+aec.Faint.Apply("($2)"))
+	s = codeRegex.ReplaceAllString(s, aec.CyanF.Apply("$1"))
+	s = boldRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return aec.Bold.Apply(m[2 : len(m)-2])
+	})
+	return s
+}
@@ -0,0 +1,307 @@
+package view
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/docker/go-units"
+	"github.com/morikuni/aec"
+	"github.com/spf13/cobra"
+
+	"go.wpm.so/cli/cli"
+	"go.wpm.so/cli/cli/command"
+	cliflags "go.wpm.so/cli/cli/flags"
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+)
+
+type viewOptions struct {
+	json     bool
+	noReadme bool
+}
+
+func NewViewCommand(wpmCli command.Cli) *cobra.Command {
+	var opts viewOptions
+
+	cmd := &cobra.Command{
+		Use:   "view [OPTIONS] PACKAGE[@VERSION] [FIELD]",
+		Short: "Show information about a package",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		Example: `  wpm view akismet
+  wpm view akismet@5.3.1
+  wpm view akismet requires.php
+  wpm view --json akismet dist`,
+		Aliases: []string{"info", "show"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var field string
+			if len(args) == 2 {
+				field = args[1]
+			}
+			return runView(cmd.Context(), wpmCli, opts, args[0], field)
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.json, "json", false, "Print the package information as JSON")
+	flags.BoolVar(&opts.noReadme, "no-readme", false, "Do not print the readme")
+
+	return cmd
+}
+
+// viewResult is a package manifest along with the versions and dist tags
+// published for it.
+type viewResult struct {
+	*manifest.Package
+	Versions []string          `json:"versions,omitempty"`
+	DistTags map[string]string `json:"dist-tags,omitempty"`
+}
+
+func runView(ctx context.Context, wpmCli command.Cli, opts viewOptions, spec, field string) error {
+	name, versionOrTag, err := parsePackageSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	client, err := wpmCli.RegistryClient()
+	if err != nil {
+		return err
+	}
+
+	var result viewResult
+	err = wpmCli.Progress().RunWithProgress("", func() error {
+		pkg, err := client.GetPackageManifest(ctx, name, versionOrTag, true)
+		if err != nil {
+			return fmt.Errorf("failed to fetch package %s@%s: %w", name, versionOrTag, err)
+		}
+		result.Package = pkg
+
+		// Not every registry serves the full document, so versions and dist
+		// tags are left out rather than failing the whole view.
+		if packument, err := client.GetPackument(ctx, name); err == nil && packument != nil {
+			result.Versions = sortVersions(packument.Versions)
+			result.DistTags = packument.DistTags
+		}
+		return nil
+	}, wpmCli.Err())
+	if err != nil {
+		return err
+	}
+
+	output.SanitizeValue(&result)
+
+	asJSON := opts.json || command.OutputFormat(wpmCli) == cliflags.FormatJSON
+
+	if field != "" {
+		value, err := lookupField(result, field)
+		if err != nil {
+			return fmt.Errorf("%s@%s: %w", result.Name, result.Version, err)
+		}
+		if s, ok := value.(string); ok && !asJSON {
+			_, _ = fmt.Fprintln(wpmCli.Out(), s)
+			return nil
+		}
+		return command.PrintJSON(wpmCli.Out(), value)
+	}
+
+	if asJSON {
+		return command.PrintJSON(wpmCli.Out(), result)
+	}
+
+	colorize := wpmCli.Out().IsColorEnabled() && command.OutputFormat(wpmCli) != cliflags.FormatPlain
+	printView(wpmCli.Out(), colorize, result)
+
+	if !opts.noReadme && strings.TrimSpace(result.Readme) != "" {
+		_, _ = fmt.Fprintln(wpmCli.Out())
+		renderReadme(wpmCli.Out(), colorize, result.Readme)
+	}
+
+	return nil
+}
+
+func parsePackageSpec(arg string) (name, versionOrTag string, err error) {
+	name = arg
+	versionOrTag = "latest"
+
+	if lastAt := strings.LastIndex(arg, "@"); lastAt > 0 {
+		name = arg[:lastAt]
+		if v := arg[lastAt+1:]; v != "" {
+			versionOrTag = v
+		}
+	}
+
+	if err := validator.IsValidPackageName(name); err != nil {
+		return "", "", fmt.Errorf("invalid package name %q: %w", name, err)
+	}
+	if validator.IsValidVersion(versionOrTag) != nil && validator.IsValidDistTag(versionOrTag) != nil {
+		return "", "", fmt.Errorf("invalid version or tag %q: must be a valid semver or dist tag", versionOrTag)
+	}
+
+	return name, versionOrTag, nil
+}
+
+// sortVersions returns the versions in semver order, oldest first. Versions
+// that don't parse are appended in string order.
+func sortVersions(versions map[string]*manifest.Package) []string {
+	var parsed []*semver.Version
+	var other []string
+	for v := range versions {
+		if sv, err := semver.NewVersion(v); err == nil {
+			parsed = append(parsed, sv)
+		} else {
+			other = append(other, v)
+		}
+	}
+	sort.Sort(semver.Collection(parsed))
+	slices.Sort(other)
+
+	sorted := make([]string, 0, len(versions))
+	for _, sv := range parsed {
+		sorted = append(sorted, sv.Original())
+	}
+	return append(sorted, other...)
+}
+
+// lookupField resolves a dotted path such as "requires.php" or "dist" in the
+// JSON form of result.
+func lookupField(result viewResult, path string) (any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	for key := range strings.SplitSeq(path, ".") {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", path)
+		}
+		if value, ok = obj[key]; !ok {
+			return nil, fmt.Errorf("field %q not found", path)
+		}
+	}
+	return value, nil
+}
+
+func printView(out io.Writer, colorize bool, r viewResult) {
+	c := func(a aec.ANSI, s string) string {
+		if !colorize {
+			return s
+		}
+		return a.Apply(s)
+	}
+
+	summary := []string{c(aec.Bold, r.Name+"@"+r.Version)}
+	if r.License != "" {
+		summary = append(summary, r.License)
+	}
+	summary = append(summary, fmt.Sprintf("deps: %d", depsCount(r.Dependencies)))
+	if len(r.Versions) > 0 {
+		summary = append(summary, fmt.Sprintf("versions: %d", len(r.Versions)))
+	}
+	_, _ = fmt.Fprintln(out, strings.Join(summary, " | "))
+
+	if r.Description != "" {
+		_, _ = fmt.Fprintln(out, r.Description)
+	}
+	if r.Homepage != "" {
+		_, _ = fmt.Fprintln(out, c(aec.CyanF, r.Homepage))
+	}
+
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintf(out, "type: %s\n", r.Type)
+	if r.Requires != nil && (r.Requires.WP != "" || r.Requires.PHP != "") {
+		_, _ = fmt.Fprintf(out, "requires: %s\n", formatRequires(r.Requires))
+	}
+	if r.Author != "" {
+		_, _ = fmt.Fprintf(out, "author: %s\n", r.Author)
+	}
+	if len(r.Tags) > 0 {
+		_, _ = fmt.Fprintf(out, "tags: %s\n", strings.Join(r.Tags, ", "))
+	}
+
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintln(out, c(aec.Bold, "dist"))
+	_, _ = fmt.Fprintf(out, "├── digest: %s\n", r.Dist.Digest)
+	_, _ = fmt.Fprintf(out, "├── files: %d\n", r.Dist.TotalFiles)
+	_, _ = fmt.Fprintf(out, "├── packed size: %s\n", units.HumanSize(float64(r.Dist.PackedSize)))
+	_, _ = fmt.Fprintf(out, "├── unpacked size: %s\n", units.HumanSize(float64(r.Dist.UnpackedSize)))
+	if len(r.Dist.Signatures) == 0 {
+		_, _ = fmt.Fprintf(out, "└── signatures: %s\n", c(aec.YellowF, "none"))
+	} else {
+		_, _ = fmt.Fprintf(out, "└── signatures: %d\n", len(r.Dist.Signatures))
+		for i, sig := range r.Dist.Signatures {
+			branch := "├──"
+			if i == len(r.Dist.Signatures)-1 {
+				branch = "└──"
+			}
+			_, _ = fmt.Fprintf(out, "    %s keyid %s\n", branch, sig.KeyID)
+		}
+	}
+
+	printMap(out, c, "dependencies", r.Dependencies)
+	printMap(out, c, "devDependencies", r.DevDependencies)
+
+	if len(r.DistTags) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, c(aec.Bold, "dist-tags"))
+		for _, tag := range sortedKeys(r.DistTags) {
+			_, _ = fmt.Fprintf(out, "%s: %s\n", c(aec.GreenF, tag), r.DistTags[tag])
+		}
+	}
+
+	if len(r.Versions) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, c(aec.Bold, "versions"))
+		_, _ = fmt.Fprintln(out, strings.Join(r.Versions, ", "))
+	}
+}
+
+func printMap(out io.Writer, c func(aec.ANSI, string) string, title string, deps *types.Dependencies) {
+	if deps == nil || len(*deps) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintln(out, c(aec.Bold, title))
+	for _, name := range sortedKeys(*deps) {
+		_, _ = fmt.Fprintf(out, "%s: %s\n", name, (*deps)[name])
+	}
+}
+
+func formatRequires(r *types.Requires) string {
+	var parts []string
+	if r.WP != "" {
+		parts = append(parts, "WordPress "+r.WP)
+	}
+	if r.PHP != "" {
+		parts = append(parts, "PHP "+r.PHP)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func depsCount(deps *types.Dependencies) int {
+	if deps == nil {
+		return 0
+	}
+	return len(*deps)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package view
+
+import (
+	"reflect"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func TestParsePackageSpec(t *testing.T) {
+	tests := []struct {
+		input         string
+		name, version string
+		wantErr       bool
+	}{
+		{input: "akismet", name: "akismet", version: "latest"},
+		{input: "akismet@5.3.1", name: "akismet", version: "5.3.1"},
+		{input: "akismet@beta", name: "akismet", version: "beta"},
+		{input: "akismet@", name: "akismet", version: "latest"},
+		{input: "Akismet", wantErr: true},
+		{input: "akismet@not a tag", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		name, version, err := parsePackageSpec(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePackageSpec(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if name != tt.name || version != tt.version {
+			t.Errorf("parsePackageSpec(%q) = %q, %q, want %q, %q", tt.input, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestLookupField(t *testing.T) {
+	result := viewResult{
+		Package: &manifest.Package{
+			Name:     "akismet",
+			Version:  "5.4.0",
+			Requires: &types.Requires{WP: ">=5.8", PHP: ">=7.2"},
+			Dist:     manifest.Dist{TotalFiles: 42},
+		},
+		DistTags: map[string]string{"latest": "5.4.0"},
+	}
+
+	tests := []struct {
+		path    string
+		want    any
+		wantErr bool
+	}{
+		{path: "requires.php", want: ">=7.2"},
+		{path: "dist.totalFiles", want: float64(42)},
+		{path: "dist-tags", want: map[string]any{"latest": "5.4.0"}},
+		{path: "requires.php.min", wantErr: true},
+		{path: "homepage", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := lookupField(result, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("lookupField(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookupField(%q) = %#v, want %#v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := map[string]*manifest.Package{
+		"5.10.0": nil, "5.2.0": nil, "5.3.0-beta.1": nil, "5.3.0": nil, "nightly": nil,
+	}
+
+	want := []string{"5.2.0", "5.3.0-beta.1", "5.3.0", "5.10.0", "nightly"}
+	if got := sortVersions(versions); !reflect.DeepEqual(got, want) {
+		t.Errorf("sortVersions() = %v, want %v", got, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"go.wpm.so/cli/cli"
 	"go.wpm.so/cli/cli/command"
+	"go.wpm.so/cli/pkg/output"
 )
 
 func NewWhoamiCommand(wpmCli command.Cli) *cobra.Command {
@@ -40,7 +41,7 @@ func runWhoami(ctx context.Context, wpmCli command.Cli) error {
 		return errors.New("failed to retrieve username")
 	}
 
-	wpmCli.Out().WriteString(username + "\n")
+	output.NewSanitizingWriter(wpmCli.Out()).WriteString(username + "\n")
 
 	return nil
 }
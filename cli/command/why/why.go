@@ -15,6 +15,7 @@ import (
 	"go.wpm.so/cli/cli"
 	"go.wpm.so/cli/cli/command"
 	"go.wpm.so/cli/cli/command/completion"
+	cliflags "go.wpm.so/cli/cli/flags"
 	"go.wpm.so/cli/pkg/output"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
 	"go.wpm.so/cli/pkg/pm/wpmlock"
@@ -70,10 +71,21 @@ func runWhy(wpmCli command.Cli, targetPkg string) error {
 		rootNode = filepath.Base(cwd)
 	}
 
-	colorize := wpmCli.Out().IsColorEnabled()
-	dependents := buildDependentsMap(config, lock, rootNode, colorize)
+	dependents := buildDependentsMap(config, lock, rootNode)
 	paths := findPathsToRoot(targetPkg, dependents)
 
+	switch command.OutputFormat(wpmCli) {
+	case cliflags.FormatJSON:
+		return command.PrintJSON(wpmCli.Out(), whyResult{
+			Name:    targetPkg,
+			Version: output.Sanitize(lock.Packages[targetPkg].Version),
+			Paths:   pathsResult(lock, paths),
+		})
+	case cliflags.FormatPlain:
+		printPlainPaths(wpmCli, lock, paths)
+		return nil
+	}
+
 	if len(paths) == 0 {
 		wpmCli.Output().Prettyln(output.Text{
 			Plain: targetPkg + " is present in lockfile but has no apparent dependents (orphaned?).",
@@ -87,11 +99,11 @@ func runWhy(wpmCli command.Cli, targetPkg string) error {
 }
 
 // buildDependentsMap returns "name -> list of packages that depend on name". Root entries
-// for the project's dependencies and devDependencies use formatted display names.
-func buildDependentsMap(config *wpmjson.Config, lock *wpmlock.Lockfile, rootNode string, colorize bool) map[string][]string {
+// for the project's dependencies and devDependencies are the root name with a suffix.
+func buildDependentsMap(config *wpmjson.Config, lock *wpmlock.Lockfile, rootNode string) map[string][]string {
 	dependents := make(map[string][]string)
-	rootNodeIdDeps := getRootNodeID(rootNode, depsSuffix, colorize)
-	rootNodeIdDevDeps := getRootNodeID(rootNode, devDepsSuffix, colorize)
+	rootNodeIdDeps := rootNode + depsSuffix
+	rootNodeIdDevDeps := rootNode + devDepsSuffix
 
 	if config.Dependencies != nil {
 		for name := range *config.Dependencies {
@@ -117,6 +129,7 @@ func buildDependentsMap(config *wpmjson.Config, lock *wpmlock.Lockfile, rootNode
 
 // printPaths renders each dependency chain from the target package up to a root entry.
 func printPaths(wpmCli command.Cli, lock *wpmlock.Lockfile, paths [][]string) {
+	colorize := wpmCli.Out().IsColorEnabled()
 	for _, path := range paths {
 		indent := ""
 		for i := len(path) - 1; i >= 0; i-- {
@@ -125,12 +138,12 @@ func printPaths(wpmCli command.Cli, lock *wpmlock.Lockfile, paths [][]string) {
 			info := ""
 			if !stringsContainsRoot(name) {
 				if pkg, ok := lock.Packages[name]; ok {
-					info = "@" + pkg.Version
+					info = "@" + output.Sanitize(pkg.Version)
 				}
 			}
 
 			if i == len(path)-1 {
-				wpmCli.Out().WriteString(fmt.Sprintf("%s%s\n", indent, name))
+				wpmCli.Out().WriteString(fmt.Sprintf("%s%s\n", indent, rootLabel(name, colorize)))
 			} else {
 				wpmCli.Out().WriteString(fmt.Sprintf("%s└─ %s%s\n", indent, name, info))
 			}
@@ -143,6 +156,59 @@ func printPaths(wpmCli command.Cli, lock *wpmlock.Lockfile, paths [][]string) {
 	}
 }
 
+// whyResult is the `wpm why --format json` document.
+type whyResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Paths   []Path `json:"paths"`
+}
+
+// Path is one chain from the project to a package. Root is "dependencies" or
+// "devDependencies", and Packages runs from the direct dependency down to the
+// package.
+type Path struct {
+	Root     string    `json:"root"`
+	Packages []Package `json:"packages"`
+}
+
+// Package is a locked package on a Path.
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// FindPaths returns every chain through which the project depends on the
+// locked package name.
+func FindPaths(config *wpmjson.Config, lock *wpmlock.Lockfile, name string) []Path {
+	return pathsResult(lock, findPathsToRoot(name, buildDependentsMap(config, lock, "")))
+}
+
+// pathsResult converts paths found by findPathsToRoot, which run from the
+// target up to a root entry.
+func pathsResult(lock *wpmlock.Lockfile, paths [][]string) []Path {
+	result := make([]Path, 0, len(paths))
+	for _, path := range paths {
+		p := Path{Root: rootKind(path[len(path)-1])}
+		for i := len(path) - 2; i >= 0; i-- {
+			p.Packages = append(p.Packages, Package{Name: path[i], Version: output.Sanitize(lock.Packages[path[i]].Version)})
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// printPlainPaths writes one tab-separated line per path: the root kind, then
+// name@version from the direct dependency down to the target.
+func printPlainPaths(wpmCli command.Cli, lock *wpmlock.Lockfile, paths [][]string) {
+	for _, p := range pathsResult(lock, paths) {
+		fields := []string{p.Root}
+		for _, pkg := range p.Packages {
+			fields = append(fields, pkg.Name+"@"+pkg.Version)
+		}
+		wpmCli.Out().WriteString(strings.Join(fields, "\t") + "\n")
+	}
+}
+
 // findPathsToRoot performs a BFS traversal backwards to find chains to the root
 func findPathsToRoot(start string, dependents map[string][]string) [][]string {
 	var results [][]string
@@ -191,6 +257,24 @@ func stringsContainsRoot(s string) bool {
 	return strings.Contains(s, "(dependencies)") || strings.Contains(s, "(devDependencies)")
 }
 
+// rootKind returns which dependency list a root entry stands for.
+func rootKind(id string) string {
+	if strings.HasSuffix(id, devDepsSuffix) {
+		return "devDependencies"
+	}
+	return "dependencies"
+}
+
+// rootLabel formats a root entry for display.
+func rootLabel(id string, colorize bool) string {
+	for _, suffix := range []string{depsSuffix, devDepsSuffix} {
+		if rootNode, ok := strings.CutSuffix(id, suffix); ok {
+			return getRootNodeID(rootNode, suffix, colorize)
+		}
+	}
+	return id
+}
+
 func getRootNodeID(rootNode, suffix string, colorize bool) string {
 	if colorize {
 		return fmt.Sprintf("%s %s", aec.Bold.Apply(rootNode), aec.Faint.Apply(suffix))
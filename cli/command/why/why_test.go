@@ -0,0 +1,33 @@
+package why
+
+import (
+	"reflect"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+func TestPathsResult(t *testing.T) {
+	config := wpmjson.New()
+	config.Dependencies = &types.Dependencies{"woocommerce": "9.0.0"}
+	config.DevDependencies = &types.Dependencies{"action-scheduler": "3.7.0"}
+
+	lock := wpmlock.New()
+	lock.Packages["woocommerce"] = wpmlock.LockPackage{
+		Version:      "9.0.0",
+		Dependencies: &types.Dependencies{"action-scheduler": "3.7.0"},
+	}
+	lock.Packages["action-scheduler"] = wpmlock.LockPackage{Version: "3.7.0"}
+
+	paths := findPathsToRoot("action-scheduler", buildDependentsMap(config, lock, "site"))
+
+	want := []Path{
+		{Root: "devDependencies", Packages: []Package{{"action-scheduler", "3.7.0"}}},
+		{Root: "dependencies", Packages: []Package{{"woocommerce", "9.0.0"}, {"action-scheduler", "3.7.0"}}},
+	}
+	if got := pathsResult(lock, paths); !reflect.DeepEqual(got, want) {
+		t.Errorf("pathsResult() = %+v, want %+v", got, want)
+	}
+}
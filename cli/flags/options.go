@@ -3,6 +3,7 @@ package flags
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/rs/zerolog"
@@ -11,12 +12,23 @@ import (
 	"go.wpm.so/cli/pkg/config"
 )
 
+// Output formats accepted by --format.
+const (
+	FormatTable = "table"
+	FormatPlain = "plain"
+	FormatJSON  = "json"
+)
+
+// Formats lists the accepted output formats.
+var Formats = []string{FormatTable, FormatPlain, FormatJSON}
+
 // ClientOptions are the options used to configure the client cli.
 type ClientOptions struct {
 	Debug     bool
 	LogLevel  string
 	ConfigDir string
 	Registry  string
+	Format    string
 }
 
 // NewClientOptions returns a new ClientOptions.
@@ -35,6 +47,32 @@ func (o *ClientOptions) InstallFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&o.LogLevel, "log-level", "l", "info", `Set the logging level ("debug", "info", "warn", "error", "fatal")`)
 }
 
+// InstallPersistentFlags adds the options every command accepts, before or
+// after its name, on the FlagSet
+func (o *ClientOptions) InstallPersistentFlags(flags *pflag.FlagSet) {
+	o.Format = FormatTable
+	flags.Var((*formatValue)(&o.Format), "format", `Output format for commands that support it ("table", "plain", "json")`)
+}
+
+// formatValue is a --format value, checked against Formats when it is set.
+type formatValue string
+
+func (f *formatValue) String() string {
+	return string(*f)
+}
+
+func (f *formatValue) Set(s string) error {
+	if !slices.Contains(Formats, s) {
+		return fmt.Errorf("must be one of %s", strings.Join(Formats, ", "))
+	}
+	*f = formatValue(s)
+	return nil
+}
+
+func (*formatValue) Type() string {
+	return "string"
+}
+
 // SetDefaultOptions sets default values for options after flag parsing is
 // complete
 func (*ClientOptions) SetDefaultOptions(_ *pflag.FlagSet) {}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+
+	"go.wpm.so/cli/pkg/output"
 )
 
 // maxErrorBodySize caps how much of an error response we'll read into memory
@@ -20,11 +22,12 @@ type HTTPError struct {
 	StatusCode int
 }
 
+// Error returns the registry's message, sanitized as it is printed as is.
 func (err *HTTPError) Error() string {
 	if err.Message == "" {
 		return "wpm registry error: " + strings.ToLower(http.StatusText(err.StatusCode))
 	}
-	return "wpm registry error: " + err.Message
+	return "wpm registry error: " + output.Sanitize(err.Message)
 }
 
 // HandleHTTPError parses a http.Response into a HTTPError. The response body
@@ -48,6 +48,12 @@ func WithContentLength(length int64) RequestOption {
 	}
 }
 
+func WithQuery(query url.Values) RequestOption {
+	return func(req *http.Request) {
+		req.URL.RawQuery = query.Encode()
+	}
+}
+
 func (c *RESTClient) DoWithContext(ctx context.Context, method, path string, body io.Reader, response any, opts ...RequestOption) error {
 	req, err := c.newRequest(ctx, method, path, body, opts...)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -39,12 +40,23 @@ const (
 	maxDecompressedSize int64 = 512 * 1024 * 1024 // 512 MB
 )
 
+// DefaultModTime is the modification time Tar records for every entry unless
+// TarOptions.ModTime is set. A fixed time keeps the archive of the same files
+// byte-for-byte identical, wherever and whenever it's built.
+var DefaultModTime = time.Date(1985, time.October, 26, 8, 15, 0, 0, time.UTC)
+
 var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
 
 type TarOptions struct {
 	IncludeFiles    []string
 	ExcludePatterns []string
 	Logger          func(format string, args ...any)
+	// ModTime, if set, is recorded by Tar as the modification time of every
+	// entry instead of DefaultModTime, e.g. from SOURCE_DATE_EPOCH.
+	ModTime time.Time
+	// Check, if set, is called by Unpack with every entry before it is
+	// extracted. An error stops the extraction.
+	Check func(hdr *tar.Header) error
 }
 
 // breakoutError is used to differentiate errors related to breaking out
@@ -212,11 +224,13 @@ func FileInfoHeader(name string, fi os.FileInfo, link string) (*tar.Header, erro
 
 type tarAppender struct {
 	TarWriter *tar.Writer
+	ModTime   time.Time
 }
 
-func newTarAppender(writer io.Writer) *tarAppender {
+func newTarAppender(writer io.Writer, modTime time.Time) *tarAppender {
 	return &tarAppender{
 		TarWriter: tar.NewWriter(writer),
+		ModTime:   modTime,
 	}
 }
 
@@ -250,6 +264,8 @@ func (ta *tarAppender) addTarFile(path, name string) error {
 		return err
 	}
 
+	hdr.ModTime = ta.ModTime.Truncate(time.Second)
+
 	originalHdrName := hdr.Name
 
 	if originalHdrName == "." {
@@ -344,7 +360,8 @@ func createTarFile(path string, hdr *tar.Header, reader io.Reader, options *TarO
 
 // Tar creates an archive from the directory at `path`, only including files whose relative
 // paths are included in `options.IncludeFiles` (if non-nil) or not in `options.ExcludePatterns`.
-func Tar(ctx context.Context, srcPath string, options *TarOptions, reporterFn func(fs.FileInfo)) (*Tarballer, error) {
+// reporterFn, if set, is called with the slash-separated relative path of every file added.
+func Tar(ctx context.Context, srcPath string, options *TarOptions, reporterFn func(name string, fi fs.FileInfo)) (*Tarballer, error) {
 	tb, err := NewTarballer(ctx, srcPath, options, reporterFn)
 	if err != nil {
 		return nil, err
@@ -365,12 +382,12 @@ type Tarballer struct {
 	compressWriter   io.WriteCloser
 	fileCount        atomic.Int64
 	unpackedSize     atomic.Int64
-	FileInfoReporter func(fs.FileInfo)
+	FileInfoReporter func(name string, fi fs.FileInfo)
 }
 
 // NewTarballer constructs a new tarballer. The arguments are the same as for
 // TarWithOptions.
-func NewTarballer(ctx context.Context, srcPath string, options *TarOptions, reporterFn func(fs.FileInfo)) (*Tarballer, error) {
+func NewTarballer(ctx context.Context, srcPath string, options *TarOptions, reporterFn func(name string, fi fs.FileInfo)) (*Tarballer, error) {
 	pm, err := patternmatcher.New(options.ExcludePatterns)
 	if err != nil {
 		return nil, err
@@ -378,7 +395,9 @@ func NewTarballer(ctx context.Context, srcPath string, options *TarOptions, repo
 
 	pipeReader, pipeWriter := io.Pipe()
 
-	zstdWriter, err := zstd.NewWriter(pipeWriter, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	// A single encoder keeps the compressed output the same regardless of
+	// GOMAXPROCS, so that archives are reproducible across machines.
+	zstdWriter, err := zstd.NewWriter(pipeWriter, zstd.WithEncoderLevel(zstd.SpeedBestCompression), zstd.WithEncoderConcurrency(1))
 	if err != nil {
 		_ = pipeReader.Close()
 		_ = pipeWriter.Close()
@@ -425,7 +444,11 @@ func (t *Tarballer) Close() error {
 //
 //nolint:gocyclo // this function is necessarily complex due to the file walking and pattern matching logic
 func (t *Tarballer) Do() {
-	ta := newTarAppender(t.compressWriter)
+	modTime := t.options.ModTime
+	if modTime.IsZero() {
+		modTime = DefaultModTime
+	}
+	ta := newTarAppender(t.compressWriter, modTime)
 
 	var doErr error
 
@@ -456,7 +479,9 @@ func (t *Tarballer) Do() {
 		return
 	}
 
-	includeFiles := t.options.IncludeFiles
+	// Walk the includes in sorted order, and WalkDir visits each directory in
+	// lexical order, so entries are always added in the same order.
+	includeFiles := slices.Sorted(slices.Values(t.options.IncludeFiles))
 	if len(includeFiles) == 0 {
 		includeFiles = []string{"."}
 	}
@@ -572,7 +597,7 @@ func (t *Tarballer) Do() {
 				t.unpackedSize.Add(fileInfo.Size())
 
 				if t.FileInfoReporter != nil {
-					t.FileInfoReporter(fileInfo)
+					t.FileInfoReporter(filepath.ToSlash(relFilePath), fileInfo)
 				}
 			}
 
@@ -643,6 +668,12 @@ loop:
 			}
 		}
 
+		if options.Check != nil {
+			if err := options.Check(hdr); err != nil {
+				return err
+			}
+		}
+
 		// Ensure that the parent directory exists.
 		err = createImpliedDirectories(dest, hdr)
 		if err != nil {
@@ -0,0 +1,54 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tarBytes(t *testing.T, dir string, options *TarOptions) []byte {
+	t.Helper()
+	tb, err := Tar(context.Background(), dir, options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(tb.Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestTarReproducible(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.php", "a.php", "inc/c.php"} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("<?php // "+name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := tarBytes(t, dir, &TarOptions{IncludeFiles: []string{"inc", "b.php", "a.php"}})
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "a.php"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	second := tarBytes(t, dir, &TarOptions{IncludeFiles: []string{"a.php", "inc", "b.php"}})
+
+	if !bytes.Equal(first, second) {
+		t.Error("archives of the same files differ")
+	}
+
+	epoch := tarBytes(t, dir, &TarOptions{IncludeFiles: []string{"a.php", "b.php", "inc"}, ModTime: time.Unix(1700000000, 0)})
+	if bytes.Equal(first, epoch) {
+		t.Error("ModTime didn't change the archive")
+	}
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"io"
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/transform"
+
+	"go.wpm.so/cli/pkg/asciisanitizer"
+)
+
+// Sanitize makes text from the registry safe to print. Control characters,
+// which could otherwise move the cursor, rewrite earlier lines or set the
+// window title, are replaced with their caret notation (ESC becomes "^[").
+// Carriage returns are replaced too unless they end a line, since a bare
+// "\r" would let the text overwrite what was printed before it on the line.
+func Sanitize(s string) string {
+	// The sanitizer rejects invalid UTF-8, so replace that first.
+	sanitized, _, err := transform.String(&asciisanitizer.Sanitizer{}, strings.ToValidUTF8(s, "\uFFFD"))
+	if err != nil {
+		return ""
+	}
+	sanitized = strings.ReplaceAll(sanitized, "\r\n", "\n")
+	return strings.ReplaceAll(sanitized, "\r", "^M")
+}
+
+// SanitizingWriter is a [Writer] that sanitizes everything written to it, for
+// printing text from the registry as a whole. Each write is sanitized on its
+// own, so writes shouldn't split a multi-byte character.
+type SanitizingWriter struct {
+	out Writer
+}
+
+// NewSanitizingWriter returns a writer that sanitizes text before writing it
+// to out.
+func NewSanitizingWriter(out Writer) *SanitizingWriter {
+	return &SanitizingWriter{out: out}
+}
+
+func (w *SanitizingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, Sanitize(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *SanitizingWriter) WriteString(s string) {
+	w.out.WriteString(Sanitize(s))
+}
+
+func (w *SanitizingWriter) IsColorEnabled() bool {
+	return w.out.IsColorEnabled()
+}
+
+// SanitizeValue applies [Sanitize] to every string reachable from v, a
+// pointer to data decoded from the registry, including map keys. Unexported
+// fields are left alone.
+func SanitizeValue(v any) {
+	sanitizeValue(reflect.ValueOf(v))
+}
+
+func sanitizeValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(Sanitize(v.String()))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			sanitizeValue(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		sanitizeValue(elem)
+		v.Set(elem)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			sanitizeValue(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			sanitizeValue(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		keys := make([]reflect.Value, 0, v.Len())
+		values := make([]reflect.Value, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key := reflect.New(v.Type().Key()).Elem()
+			key.Set(iter.Key())
+			sanitizeValue(key)
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			sanitizeValue(value)
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+		v.Clear()
+		for i := range keys {
+			v.SetMapIndex(keys[i], values[i])
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testWriter struct {
+	bytes.Buffer
+}
+
+func (w *testWriter) WriteString(s string) { w.Buffer.WriteString(s) }
+func (w *testWriter) IsColorEnabled() bool { return true }
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain text", input: "Akismet 5.4.0 – anti-spam", want: "Akismet 5.4.0 – anti-spam"},
+		{name: "newlines and tabs", input: "a\tb\nc", want: "a\tb\nc"},
+		{name: "crlf", input: "a\r\nb", want: "a\nb"},
+		{name: "color", input: "\x1b[31mred\x1b[0m", want: "^[[31mred^[[0m"},
+		{name: "clear screen", input: "\x1b[2J\x1b[H", want: "^[[2J^[[H"},
+		{name: "cursor up and erase line", input: "\x1b[1A\x1b[2Kok", want: "^[[1A^[[2Kok"},
+		{name: "window title", input: "\x1b]0;pwned\x07", want: "^[]0;pwned^G"},
+		{name: "hyperlink", input: "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", want: "^[]8;;https://evil.example^[\\click^[]8;;^[\\"},
+		{name: "device control string", input: "\x1bP+q544e\x1b\\", want: "^[P+q544e^[\\"},
+		{name: "c1 csi", input: "\u009b31mred", want: "^[31mred"},
+		{name: "c1 osc", input: "\u009d0;pwned\u009c", want: `^]0;pwned^\\`},
+		{name: "carriage return overwrite", input: "evil\rverified", want: "evil^Mverified"},
+		{name: "backspace", input: "abc\b\b\bxyz", want: "abc^H^H^Hxyz"},
+		{name: "bell", input: "\a", want: "^G"},
+		{name: "nul", input: "a\x00b", want: "a^@b"},
+		{name: "invalid utf-8", input: "a\xffb", want: "a�b"},
+	}
+
+	for _, tt := range tests {
+		if got := Sanitize(tt.input); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizingWriter(t *testing.T) {
+	var out testWriter
+	w := NewSanitizingWriter(&out)
+
+	_, _ = fmt.Fprintf(w, "%s@%s\n", "akismet", "5.4.0\x1b[2J")
+	w.WriteString("\u009b1A\r\n")
+
+	want := "akismet@5.4.0^[[2J\n^[1A\n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if strings.ContainsAny(out.String(), "\x1b\u009b\r") {
+		t.Errorf("output still contains control characters: %q", out.String())
+	}
+	if !w.IsColorEnabled() {
+		t.Error("IsColorEnabled() = false, want the wrapped writer's value")
+	}
+}
+
+func TestSanitizeValue(t *testing.T) {
+	type release struct {
+		Version string
+		Notes   *string
+	}
+	notes := "fixed\r\x1b[2Ksafe"
+	v := struct {
+		Name     string
+		Tags     []string
+		DistTags map[string]string
+		Extra    map[string]any
+		Releases []release
+		hidden   string
+	}{
+		Name:     "akismet\x1b]0;title\x07",
+		Tags:     []string{"spam\u009b2J"},
+		DistTags: map[string]string{"latest\x1b[8m": "5.4.0\x1b[8m"},
+		Extra:    map[string]any{"author": "\x1b[31mme", "count": 3},
+		Releases: []release{{Version: "5.4.0\b", Notes: &notes}},
+		hidden:   "\x1b",
+	}
+
+	SanitizeValue(&v)
+
+	if v.Name != "akismet^[]0;title^G" {
+		t.Errorf("Name = %q", v.Name)
+	}
+	if v.Tags[0] != "spam^[2J" {
+		t.Errorf("Tags[0] = %q", v.Tags[0])
+	}
+	if v.DistTags["latest^[[8m"] != "5.4.0^[[8m" || len(v.DistTags) != 1 {
+		t.Errorf("DistTags = %q", v.DistTags)
+	}
+	if v.Extra["author"] != "^[[31mme" || v.Extra["count"] != 3 {
+		t.Errorf("Extra = %v", v.Extra)
+	}
+	if v.Releases[0].Version != "5.4.0^H" || *v.Releases[0].Notes != "fixed^M^[[2Ksafe" {
+		t.Errorf("Releases[0] = %q, %q", v.Releases[0].Version, *v.Releases[0].Notes)
+	}
+	if v.hidden != "\x1b" {
+		t.Errorf("unexported field changed to %q", v.hidden)
+	}
+}
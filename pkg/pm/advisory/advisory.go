@@ -0,0 +1,117 @@
+// Package advisory matches packages against a database of security
+// advisories.
+package advisory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Severity is how serious a vulnerability is.
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityModerate Severity = "moderate"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities lists the severities from least to most serious.
+var Severities = []Severity{SeverityLow, SeverityModerate, SeverityHigh, SeverityCritical}
+
+// Rank orders severities from 1 (low) to 4 (critical). Unknown severities
+// rank 0.
+func (s Severity) Rank() int {
+	return slices.Index(Severities, s) + 1
+}
+
+func (s Severity) Valid() bool {
+	return s.Rank() > 0
+}
+
+// Advisory describes a vulnerability in a range of versions of a package.
+//
+// Example:
+//
+//	{
+//	    "id": "WPM-2024-0042",
+//	    "package": "contact-form-7",
+//	    "title": "Unrestricted file upload",
+//	    "severity": "high",
+//	    "vulnerable": "<5.9.2",
+//	    "patched": ["5.9.2"],
+//	    "cves": ["CVE-2024-2242"],
+//	    "url": "https://example.com/advisories/WPM-2024-0042"
+//	}
+type Advisory struct {
+	ID         string   `json:"id"`
+	Package    string   `json:"package"`
+	Title      string   `json:"title"`
+	Severity   Severity `json:"severity"`
+	Vulnerable string   `json:"vulnerable"` // semver constraint, e.g. ">=2.0.0 <2.4.3 || <1.9.9"
+	Patched    []string `json:"patched,omitempty"`
+	CVEs       []string `json:"cves,omitempty"`
+	URL        string   `json:"url,omitempty"`
+}
+
+// Affects reports whether version is in the advisory's vulnerable range.
+func (a Advisory) Affects(version string) (bool, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+	c, err := semver.NewConstraint(a.Vulnerable)
+	if err != nil {
+		return false, fmt.Errorf("advisory %s has an invalid vulnerable range %q: %w", a.ID, a.Vulnerable, err)
+	}
+	return c.Check(v), nil
+}
+
+// Database is a set of advisories, as served by the registry or read from a
+// local file.
+type Database struct {
+	Advisories []Advisory `json:"advisories"`
+}
+
+// ReadFile reads a database from a JSON file.
+func ReadFile(path string) (*Database, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read advisory database: %w", err)
+	}
+	var db Database
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("failed to parse advisory database %s: %w", path, err)
+	}
+	return &db, nil
+}
+
+// Match returns the advisories affecting version of the package name.
+func (db *Database) Match(name, version string) ([]Advisory, error) {
+	var matched []Advisory
+	for _, a := range db.Advisories {
+		if a.Package != name {
+			continue
+		}
+		affected, err := a.Affects(version)
+		if err != nil {
+			return nil, err
+		}
+		if affected {
+			matched = append(matched, a)
+		}
+	}
+	return matched, nil
+}
+
+// Affected reports whether any advisory affects version of the package name.
+// A version that can't be checked counts as affected.
+func (db *Database) Affected(name, version string) bool {
+	matched, err := db.Match(name, version)
+	return err != nil || len(matched) > 0
+}
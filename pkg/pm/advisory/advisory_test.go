@@ -0,0 +1,53 @@
+package advisory
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	db := &Database{Advisories: []Advisory{
+		{ID: "A-1", Package: "akismet", Severity: SeverityHigh, Vulnerable: "<5.3.1"},
+		{ID: "A-2", Package: "akismet", Severity: SeverityLow, Vulnerable: ">=2.0.0 <2.4.3 || <1.9.9"},
+		{ID: "A-3", Package: "hello-dolly", Severity: SeverityCritical, Vulnerable: "*"},
+	}}
+
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{version: "5.4.0"},
+		{version: "5.3.0", want: []string{"A-1"}},
+		{version: "2.4.0", want: []string{"A-1", "A-2"}},
+		{version: "1.9.9", want: []string{"A-1"}},
+	}
+	for _, tt := range tests {
+		matched, err := db.Match("akismet", tt.version)
+		if err != nil {
+			t.Fatalf("Match(%q) error = %v", tt.version, err)
+		}
+		var ids []string
+		for _, a := range matched {
+			ids = append(ids, a.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("Match(%q) = %v, want %v", tt.version, ids, tt.want)
+		}
+	}
+
+	if _, err := db.Match("akismet", "not-a-version"); err == nil {
+		t.Error("Match(invalid version) = nil error, want an error")
+	}
+	if !db.Affected("akismet", "not-a-version") {
+		t.Error("Affected(invalid version) = false, want true")
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if SeverityCritical.Rank() <= SeverityHigh.Rank() || SeverityModerate.Rank() <= SeverityLow.Rank() {
+		t.Error("severities are not ranked from low to critical")
+	}
+	if Severity("urgent").Valid() {
+		t.Error(`Severity("urgent").Valid() = true, want false`)
+	}
+}
@@ -0,0 +1,365 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/sync/errgroup"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+	"go.wpm.so/cli/pkg/version"
+	"go.wpm.so/cli/pkg/wp/layout"
+)
+
+const (
+	ComposerFile     = "composer.json"
+	ComposerLockFile = "composer.lock"
+
+	maxComposerFileBytes = 8 << 20
+	resolveWorkers       = 16
+)
+
+// Packages that install WordPress core. Their version becomes
+// config.runtime.wp.
+var composerCorePackages = []string{
+	"roots/wordpress",
+	"roots/wordpress-full",
+	"roots/wordpress-no-content",
+	"johnpbloch/wordpress",
+	"johnpbloch/wordpress-core",
+}
+
+// Composer plugins whose only job is to put WordPress packages in place,
+// which wpm does itself.
+var composerInstallers = []string{
+	"composer/installers",
+	"oomphinc/composer-installers-extender",
+	"roots/wordpress-core-installer",
+	"johnpbloch/wordpress-core-installer",
+}
+
+type composerJSON struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+	Extra      struct {
+		InstallerPaths      map[string][]string `json:"installer-paths"`
+		WordPressInstallDir json.RawMessage     `json:"wordpress-install-dir"`
+	} `json:"extra"`
+}
+
+type composerLock struct {
+	Packages    []composerLockPackage `json:"packages"`
+	PackagesDev []composerLockPackage `json:"packages-dev"`
+}
+
+type composerLockPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// composerRequirement is a WPackagist requirement waiting for a version.
+type composerRequirement struct {
+	composerName string
+	name         string
+	pkgType      types.PackageType
+	constraint   string
+	dev          bool
+	version      string
+	reason       string
+}
+
+// FromComposer reads composer.json, and composer.lock when present, from dir.
+// WPackagist plugins and themes become dependencies at their locked version,
+// or at the version their constraint pins. Other constraints are resolved
+// against the versions resolve lists, which may be nil to skip them. Installer paths become the
+// content, plugin and theme directories.
+func FromComposer(ctx context.Context, dir string, resolve VersionLister) (*Result, error) {
+	var manifest composerJSON
+	if err := readComposerFile(filepath.Join(dir, ComposerFile), &manifest); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s not found in %s", ComposerFile, dir)
+		}
+		return nil, err
+	}
+
+	locked := make(map[string]string)
+	var lock composerLock
+	err := readComposerFile(filepath.Join(dir, ComposerLockFile), &lock)
+	switch {
+	case err == nil:
+		for _, p := range slices.Concat(lock.Packages, lock.PackagesDev) {
+			locked[p.Name] = p.Version
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	r := newResult()
+
+	var reqs []*composerRequirement
+	collect := func(require map[string]string, dev bool) {
+		for _, name := range slices.Sorted(maps.Keys(require)) {
+			if req := r.composerRequirement(name, require[name], dev, locked); req != nil {
+				reqs = append(reqs, req)
+			}
+		}
+	}
+	collect(manifest.Require, false)
+	collect(manifest.RequireDev, true)
+
+	if err := resolveRequirements(ctx, reqs, resolve); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]types.PackageType)
+	for _, req := range reqs {
+		if req.reason != "" {
+			r.unmapped(req.composerName, "%s", req.reason)
+			continue
+		}
+		r.add(req.name, req.version, req.dev, req.pkgType, seen)
+	}
+
+	r.applyInstallerPaths(manifest.Extra.InstallerPaths)
+	if hasCorePackage(manifest) {
+		r.checkCoreInstallDir(manifest.Extra.WordPressInstallDir, layout.Detect(dir).Core())
+	}
+
+	r.sortUnmapped()
+	return r, nil
+}
+
+// composerRequirement classifies one require entry. WPackagist packages are
+// returned for version resolution; everything else is recorded on r.
+func (r *Result) composerRequirement(name, constraint string, dev bool, locked map[string]string) *composerRequirement {
+	vendor, slug, _ := strings.Cut(name, "/")
+
+	switch {
+	case vendor == "wpackagist-plugin" || vendor == "wpackagist-theme":
+		pkgType := types.TypePlugin
+		if vendor == "wpackagist-theme" {
+			pkgType = types.TypeTheme
+		}
+		req := &composerRequirement{composerName: name, name: slug, pkgType: pkgType, constraint: constraint, dev: dev}
+		if v, err := version.Normalize(locked[name]); err == nil {
+			req.version = v
+		} else if v, ok := version.ComposerExact(constraint); ok {
+			req.version = v
+		}
+		return req
+
+	case slices.Contains(composerCorePackages, name):
+		if v, err := version.Normalize(locked[name]); err == nil {
+			r.RuntimeWP = v
+		} else if v, ok := version.ComposerExact(constraint); ok {
+			r.RuntimeWP = v
+		} else {
+			r.unmapped(name, "WordPress core %s is not locked to a version; set config.runtime.wp", constraint)
+		}
+
+	case slices.Contains(composerInstallers, name):
+		r.unmapped(name, "Composer installer, not needed with wpm")
+
+	case !strings.Contains(name, "/"):
+		r.unmapped(name, "platform requirement")
+
+	default:
+		r.unmapped(name, "not a WPackagist plugin or theme")
+	}
+	return nil
+}
+
+// resolveRequirements pins requirements that only have a range to the highest
+// registry version that satisfies the range.
+func resolveRequirements(ctx context.Context, reqs []*composerRequirement, resolve VersionLister) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(resolveWorkers)
+
+	for _, req := range reqs {
+		if req.version != "" {
+			continue
+		}
+
+		constraint, err := version.ComposerConstraint(req.constraint)
+		if err != nil {
+			req.reason = err.Error()
+			continue
+		}
+		if resolve == nil {
+			req.reason = fmt.Sprintf("%s is a range and %s has no locked version", req.constraint, ComposerLockFile)
+			continue
+		}
+		c, err := semver.NewConstraint(constraint)
+		if err != nil {
+			req.reason = err.Error()
+			continue
+		}
+
+		g.Go(func() error {
+			versions, err := resolve(gctx, req.name)
+			if ctxErr := gctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				req.reason = fmt.Sprintf("cannot resolve %s: %v", req.constraint, err)
+				return nil
+			}
+			best := highestSatisfying(versions, c)
+			if best == nil {
+				req.reason = fmt.Sprintf("no published version satisfies %s", req.constraint)
+				return nil
+			}
+			req.version = best.String()
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// highestSatisfying returns the highest of versions that satisfies c, or nil.
+// Versions that aren't valid semver are skipped.
+func highestSatisfying(versions []string, c *semver.Constraints) *semver.Version {
+	var best *semver.Version
+	for _, raw := range versions {
+		v, err := semver.StrictNewVersion(raw)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if best == nil || v.GreaterThan(best) {
+			best = v
+		}
+	}
+	return best
+}
+
+// applyInstallerPaths maps composer/installers paths for WordPress plugins
+// and themes onto the content, plugin and theme directories.
+func (r *Result) applyInstallerPaths(paths map[string][]string) {
+	var pluginDir, themeDir string
+
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		for _, selector := range paths[p] {
+			var dst *string
+			switch selector {
+			case "type:wordpress-plugin", "vendor:wpackagist-plugin":
+				dst = &pluginDir
+			case "type:wordpress-theme", "vendor:wpackagist-theme":
+				dst = &themeDir
+			case "type:wordpress-muplugin", "type:wordpress-dropin", "type:wordpress-core":
+				// Not installed by wpm.
+				continue
+			default:
+				if strings.HasPrefix(selector, "wpackagist-") {
+					r.unmapped(selector, "per-package installer path %s is not supported", p)
+				}
+				continue
+			}
+
+			dir, ok := installerDir(p)
+			if !ok {
+				r.unmapped(selector, "installer path %s must be a project directory ending in {$name}", p)
+				continue
+			}
+			*dst = dir
+		}
+	}
+
+	switch {
+	case path.Base(pluginDir) == "plugins":
+		r.ContentDir = path.Dir(pluginDir)
+	case path.Base(themeDir) == "themes":
+		r.ContentDir = path.Dir(themeDir)
+	}
+	if r.ContentDir == "." {
+		r.ContentDir = ""
+	}
+
+	contentDir := r.ContentDir
+	if contentDir == "" {
+		contentDir = "wp-content"
+	}
+	if pluginDir != "" && pluginDir != path.Join(contentDir, "plugins") {
+		r.PluginDir = pluginDir
+	}
+	if themeDir != "" && themeDir != path.Join(contentDir, "themes") {
+		r.ThemeDir = themeDir
+	}
+	if r.ContentDir == "wp-content" {
+		r.ContentDir = ""
+	}
+}
+
+// installerDir strips the trailing {$name} from an installer path.
+func installerDir(p string) (string, bool) {
+	dir, ok := strings.CutSuffix(strings.TrimSuffix(p, "/"), "/{$name}")
+	if !ok || strings.Contains(dir, "{$") {
+		return "", false
+	}
+	dir = path.Clean(dir)
+	if validator.IsValidProjectRelPath(dir) != nil {
+		return "", false
+	}
+	return dir, true
+}
+
+// checkCoreInstallDir reports a core install directory other than coreDir,
+// which is where wpm core installs WordPress: the project root, or web/wp in
+// Bedrock.
+func (r *Result) checkCoreInstallDir(raw json.RawMessage, coreDir string) {
+	if len(raw) == 0 {
+		return
+	}
+	var dir string
+	if err := json.Unmarshal(raw, &dir); err != nil {
+		r.unmapped("wordpress-install-dir", "per-package install directories are not supported")
+		return
+	}
+	if path.Clean(dir) != coreDir {
+		where := "the project root"
+		if coreDir != "." {
+			where = coreDir
+		}
+		r.unmapped("wordpress-install-dir", "wpm installs WordPress core in %s, not %s", where, dir)
+	}
+}
+
+func hasCorePackage(m composerJSON) bool {
+	for _, name := range composerCorePackages {
+		if _, ok := m.Require[name]; ok {
+			return true
+		}
+		if _, ok := m.RequireDev[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func readComposerFile(p string, v any) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if fi.Size() > maxComposerFileBytes {
+		return fmt.Errorf("%s is larger than %d bytes", filepath.Base(p), maxComposerFileBytes)
+	}
+	data, err := os.ReadFile(p) //nolint:gosec // p is the project dir + a constant
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(p), err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+const bedrockComposerJSON = `{
+  "name": "roots/bedrock",
+  "require": {
+    "php": ">=8.1",
+    "composer/installers": "^2.2",
+    "roots/wordpress": "6.5.2",
+    "wpackagist-plugin/akismet": "^5.0",
+    "wpackagist-plugin/wordpress-seo": "^22.0",
+    "wpackagist-plugin/contact-form-7": "*",
+    "wpackagist-plugin/old-plugin": "^1.0",
+    "wpackagist-plugin/missing": "*",
+    "wpackagist-plugin/branch": "dev-trunk",
+    "wpackagist-theme/twentytwentyfour": "1.1",
+    "acme/private-plugin": "^1.0"
+  },
+  "require-dev": {
+    "wpackagist-plugin/query-monitor": "~3.15"
+  },
+  "extra": {
+    "installer-paths": {
+      "web/app/mu-plugins/{$name}/": ["type:wordpress-muplugin"],
+      "web/app/plugins/{$name}/": ["type:wordpress-plugin"],
+      "web/app/themes/{$name}/": ["type:wordpress-theme"],
+      "web/app/custom/{$name}/": ["wpackagist-plugin/akismet"]
+    },
+    "wordpress-install-dir": "web/wp"
+  }
+}`
+
+const bedrockComposerLock = `{
+  "packages": [
+    {"name": "roots/wordpress", "version": "6.5.2"},
+    {"name": "wpackagist-plugin/akismet", "version": "5.3"},
+    {"name": "wpackagist-plugin/wordpress-seo", "version": "22.6"},
+    {"name": "wpackagist-plugin/branch", "version": "dev-trunk"}
+  ],
+  "packages-dev": [
+    {"name": "wpackagist-plugin/query-monitor", "version": "3.15.0"}
+  ]
+}`
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromComposer(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ComposerFile, bedrockComposerJSON)
+	writeFile(t, dir, ComposerLockFile, bedrockComposerLock)
+
+	published := map[string][]string{
+		"contact-form-7": {"5.9.2", "6.0.0-beta.1", "5.9.3"},
+		"old-plugin":     {"1.0.0", "1.4.2", "2.0.0"},
+	}
+	resolve := func(_ context.Context, name string) ([]string, error) {
+		if versions, ok := published[name]; ok {
+			return versions, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	r, err := FromComposer(context.Background(), dir, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantDeps := types.Dependencies{
+		"akismet":          "5.3.0",
+		"wordpress-seo":    "22.6.0",
+		"contact-form-7":   "5.9.3",
+		"old-plugin":       "1.4.2",
+		"twentytwentyfour": "1.1.0",
+	}
+	if !maps.Equal(r.Dependencies, wantDeps) {
+		t.Errorf("Dependencies = %v, want %v", r.Dependencies, wantDeps)
+	}
+	if want := (types.Dependencies{"query-monitor": "3.15.0"}); !maps.Equal(r.DevDependencies, want) {
+		t.Errorf("DevDependencies = %v, want %v", r.DevDependencies, want)
+	}
+	if r.ContentDir != "web/app" || r.PluginDir != "" || r.ThemeDir != "" {
+		t.Errorf("dirs = %q %q %q, want web/app with default plugin and theme dirs", r.ContentDir, r.PluginDir, r.ThemeDir)
+	}
+	if r.RuntimeWP != "6.5.2" {
+		t.Errorf("RuntimeWP = %q, want 6.5.2", r.RuntimeWP)
+	}
+
+	wantUnmapped := []string{
+		"acme/private-plugin",
+		"composer/installers",
+		"php",
+		"wordpress-install-dir",
+		"wpackagist-plugin/akismet",
+		"wpackagist-plugin/branch",
+		"wpackagist-plugin/missing",
+	}
+	var got []string
+	for _, u := range r.Unmapped {
+		got = append(got, u.Name)
+	}
+	if len(got) != len(wantUnmapped) {
+		t.Fatalf("Unmapped = %v, want %v", r.Unmapped, wantUnmapped)
+	}
+	for i := range got {
+		if got[i] != wantUnmapped[i] {
+			t.Errorf("Unmapped[%d] = %q, want %q", i, got[i], wantUnmapped[i])
+		}
+	}
+}
+
+func TestFromComposerBedrockCoreDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"config", "web"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, dir, "config/application.php", "<?php\n")
+	writeFile(t, dir, "web/wp-config.php", "<?php\n")
+	writeFile(t, dir, ComposerFile, `{"require": {"roots/wordpress": "6.5.2"}, "extra": {"wordpress-install-dir": "web/wp"}}`)
+
+	r, err := FromComposer(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Unmapped) != 0 {
+		t.Errorf("Unmapped = %v, want web/wp accepted as the Bedrock core directory", r.Unmapped)
+	}
+}
+
+func TestFromComposerWithoutLockOrRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ComposerFile, `{"require": {"wpackagist-plugin/akismet": "5.3", "wpackagist-plugin/jetpack": "^13.0"}}`)
+
+	r, err := FromComposer(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (types.Dependencies{"akismet": "5.3.0"}); !maps.Equal(r.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", r.Dependencies, want)
+	}
+	if len(r.Unmapped) != 1 || r.Unmapped[0].Name != "wpackagist-plugin/jetpack" {
+		t.Errorf("Unmapped = %v, want the jetpack range", r.Unmapped)
+	}
+}
+
+func TestApply(t *testing.T) {
+	cfg := wpmjson.New()
+	deps := types.Dependencies{"akismet": "5.0.0", "query-monitor": "3.0.0", "hello-dolly": "1.7.2"}
+	cfg.Dependencies = &deps
+
+	r := newResult()
+	r.Dependencies["akismet"] = "5.3.0"
+	r.DevDependencies["query-monitor"] = "3.15.0"
+	r.ContentDir = "web/app"
+	r.Apply(cfg)
+
+	wantDeps := types.Dependencies{"akismet": "5.3.0", "hello-dolly": "1.7.2"}
+	if !maps.Equal(*cfg.Dependencies, wantDeps) {
+		t.Errorf("Dependencies = %v, want %v", *cfg.Dependencies, wantDeps)
+	}
+	if cfg.DevDependencies == nil || (*cfg.DevDependencies)["query-monitor"] != "3.15.0" {
+		t.Errorf("DevDependencies = %v, want query-monitor moved over", cfg.DevDependencies)
+	}
+	if cfg.ContentDir() != "web/app" {
+		t.Errorf("content-dir = %q, want web/app", cfg.ContentDir())
+	}
+}
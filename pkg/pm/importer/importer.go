@@ -0,0 +1,125 @@
+// Package importer converts package lists kept by other tools into wpm
+// dependencies.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+)
+
+// Unmapped is an entry of the source list that could not become a wpm
+// dependency.
+type Unmapped struct {
+	Name   string
+	Reason string
+}
+
+// Result holds what an import found. Directory fields are project-relative
+// and empty when the source keeps the WordPress default.
+type Result struct {
+	Dependencies    types.Dependencies
+	DevDependencies types.Dependencies
+	ContentDir      string
+	PluginDir       string
+	ThemeDir        string
+	RuntimeWP       string
+	Unmapped        []Unmapped
+}
+
+// VersionLister returns every version of a package published on the
+// registry.
+type VersionLister func(ctx context.Context, name string) ([]string, error)
+
+func newResult() *Result {
+	return &Result{
+		Dependencies:    types.Dependencies{},
+		DevDependencies: types.Dependencies{},
+	}
+}
+
+func (r *Result) unmapped(name, format string, args ...any) {
+	r.Unmapped = append(r.Unmapped, Unmapped{Name: name, Reason: fmt.Sprintf(format, args...)})
+}
+
+// add records name at version, refusing names wpm can't use and names
+// already taken by a package of another type.
+func (r *Result) add(name, version string, dev bool, pkgType types.PackageType, seen map[string]types.PackageType) {
+	if err := validator.IsValidPackageName(name); err != nil {
+		r.unmapped(name, "not a valid package name")
+		return
+	}
+	if t, ok := seen[name]; ok && t != pkgType {
+		r.unmapped(name, "a %s with the same name is already listed", t)
+		return
+	}
+	seen[name] = pkgType
+
+	if dev {
+		r.DevDependencies[name] = version
+		return
+	}
+	r.Dependencies[name] = version
+}
+
+// Apply merges the result into cfg. Imported versions replace the ones
+// already listed, and a package moves between dependencies and
+// devDependencies to match the source. Directories and the runtime are only
+// set when the config doesn't already define them.
+func (r *Result) Apply(cfg *wpmjson.Config) {
+	cfg.Dependencies = mergeDependencies(cfg.Dependencies, r.Dependencies, r.DevDependencies)
+	cfg.DevDependencies = mergeDependencies(cfg.DevDependencies, r.DevDependencies, r.Dependencies)
+
+	if r.ContentDir == "" && r.PluginDir == "" && r.ThemeDir == "" && r.RuntimeWP == "" {
+		return
+	}
+	if cfg.Config == nil {
+		cfg.Config = &types.PackageConfig{}
+	}
+	setIfEmpty(&cfg.Config.ContentDir, r.ContentDir)
+	setIfEmpty(&cfg.Config.PluginDir, r.PluginDir)
+	setIfEmpty(&cfg.Config.ThemeDir, r.ThemeDir)
+	if r.RuntimeWP != "" {
+		if cfg.Config.Runtime == nil {
+			cfg.Config.Runtime = &types.Runtime{}
+		}
+		setIfEmpty(&cfg.Config.Runtime.WP, r.RuntimeWP)
+	}
+}
+
+// mergeDependencies adds src to dst and drops the names listed in moved,
+// returning nil when nothing is left.
+func mergeDependencies(dst *types.Dependencies, src, moved types.Dependencies) *types.Dependencies {
+	merged := types.Dependencies{}
+	if dst != nil {
+		merged = *dst
+	}
+	for name := range moved {
+		delete(merged, name)
+	}
+	for name, v := range src {
+		merged[name] = v
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return &merged
+}
+
+func setIfEmpty(dst *string, v string) {
+	if *dst == "" {
+		*dst = v
+	}
+}
+
+// sortUnmapped orders unmapped entries by name for stable output.
+func (r *Result) sortUnmapped() {
+	slices.SortStableFunc(r.Unmapped, func(a, b Unmapped) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,45 @@
+package importer
+
+import (
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/version"
+	"go.wpm.so/cli/pkg/wp/wpcli"
+)
+
+// FromWPCLI turns a `wp plugin list` or `wp theme list` dump into
+// dependencies pinned to the reported versions. Must-use plugins and
+// drop-ins are reported, since wpm doesn't install them.
+func FromWPCLI(items []wpcli.Item, pkgType types.PackageType) *Result {
+	r := newResult()
+	seen := make(map[string]types.PackageType)
+
+	for _, item := range items {
+		switch item.Status {
+		case wpcli.StatusMustUse:
+			r.unmapped(item.Name, "must-use plugin")
+			continue
+		case wpcli.StatusDropin:
+			r.unmapped(item.Name, "drop-in")
+			continue
+		}
+
+		if item.Version == "" {
+			r.unmapped(item.Name, "no version reported")
+			continue
+		}
+		v, err := version.Normalize(item.Version)
+		if err != nil {
+			r.unmapped(item.Name, "unrecognized version %q", item.Version)
+			continue
+		}
+		if _, dup := seen[item.Name]; dup {
+			r.unmapped(item.Name, "listed more than once")
+			continue
+		}
+
+		r.add(item.Name, v, false, pkgType, seen)
+	}
+
+	r.sortUnmapped()
+	return r
+}
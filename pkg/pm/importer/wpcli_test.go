@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/wp/wpcli"
+)
+
+const pluginListDump = `[
+  {"name":"akismet","status":"active","update":"available","version":"5.3"},
+  {"name":"hello","status":"inactive","update":"none","version":"1.7.2"},
+  {"name":"Bad_Name","status":"inactive","update":"none","version":"1.0.0"},
+  {"name":"no-version","status":"inactive","update":"none","version":""},
+  {"name":"odd-version","status":"inactive","update":"none","version":"trunk"},
+  {"name":"object-cache.php","status":"dropin","update":"none","version":""},
+  {"name":"loader","status":"must-use","update":"none","version":"1.0"}
+]`
+
+func TestFromWPCLI(t *testing.T) {
+	items, err := wpcli.Read(strings.NewReader(pluginListDump))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := FromWPCLI(items, types.TypePlugin)
+
+	want := types.Dependencies{"akismet": "5.3.0", "hello": "1.7.2"}
+	if !maps.Equal(r.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", r.Dependencies, want)
+	}
+
+	wantUnmapped := []string{"Bad_Name", "loader", "no-version", "object-cache.php", "odd-version"}
+	if len(r.Unmapped) != len(wantUnmapped) {
+		t.Fatalf("Unmapped = %v, want %v", r.Unmapped, wantUnmapped)
+	}
+	for i, u := range r.Unmapped {
+		if u.Name != wantUnmapped[i] {
+			t.Errorf("Unmapped[%d] = %q, want %q", i, u.Name, wantUnmapped[i])
+		}
+	}
+}
@@ -8,18 +8,21 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"go.wpm.so/cli/pkg/archive"
+	"go.wpm.so/cli/pkg/pm/policy"
 	"go.wpm.so/cli/pkg/pm/registry"
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
@@ -33,20 +36,22 @@ const (
 
 type Installer struct {
 	concurrency int
-	contentDir  string
+	dirs        Dirs
 	tmpDir      string
 	runDir      string
 
 	client     registry.Client
+	policy     *policy.Policy // nil unless wpm.json sets an install policy
 	extractSem chan struct{}
 	logger     func(format string, args ...any)
 }
 
 func New(
 	ctx context.Context,
-	contentDir string,
+	dirs Dirs,
 	concurrency int,
 	client registry.Client,
+	pol *policy.Policy,
 	logger func(format string, args ...any),
 ) (*Installer, error) {
 	if concurrency <= 0 {
@@ -54,11 +59,11 @@ func New(
 	}
 
 	//nolint:gosec // Dir perms are intentionally permissive here.
-	if err := os.MkdirAll(contentDir, 0o755); err != nil {
+	if err := os.MkdirAll(dirs.Content, 0o755); err != nil {
 		return nil, fmt.Errorf("failed to create content directory: %w", err)
 	}
 
-	tmpDir := filepath.Join(contentDir, ".tmp")
+	tmpDir := filepath.Join(dirs.Content, ".tmp")
 	//nolint:gosec // Dir perms are intentionally permissive here.
 	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
 		return nil, fmt.Errorf("failed to create tmp directory: %w", err)
@@ -73,7 +78,8 @@ func New(
 
 	return &Installer{
 		client:      client,
-		contentDir:  contentDir,
+		policy:      pol,
+		dirs:        dirs,
 		tmpDir:      tmpDir,
 		runDir:      runDir,
 		concurrency: concurrency,
@@ -103,17 +109,33 @@ func sweepStaleRunDirs(tmpDir string) {
 	}
 }
 
-func (i *Installer) InstallAll(ctx context.Context, plan []Action, progressFn func(Action)) error {
+// InstallAll carries out the plan. Every package it installs or updates is
+// first downloaded into the staging area and checked against the install
+// policy, as are the installed packages it leaves alone. Nothing in the
+// install directories changes unless all of them pass; otherwise every
+// violation is reported together.
+func (i *Installer) InstallAll(ctx context.Context, plan []Action, installed []Installed, progressFn func(Action)) error {
+	staged, err := i.stageAll(ctx, plan)
+	if err != nil {
+		return err
+	}
+	violations, err := CheckInstalled(i.policy, i.dirs, installed)
+	if err != nil {
+		return err
+	}
+	if violations = append(staged.violations, violations...); len(violations) > 0 {
+		return &policy.Error{Violations: violations}
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(i.concurrency)
-
-	for _, action := range plan {
+	for idx, action := range plan {
 		g.Go(func() error {
 			if err := ctx.Err(); err != nil {
 				return err
 			}
 
-			err := i.install(ctx, action)
+			err := i.install(ctx, action, staged.dirs[idx])
 			if err == nil && progressFn != nil {
 				progressFn(action)
 			}
@@ -123,7 +145,69 @@ func (i *Installer) InstallAll(ctx context.Context, plan []Action, progressFn fu
 	return g.Wait()
 }
 
-func (i *Installer) install(ctx context.Context, action Action) error {
+// stagedPlan is a plan whose packages are extracted in the staging area.
+type stagedPlan struct {
+	dirs       []string // extracted package of each action, "" for removals
+	violations []policy.Violation
+}
+
+// stageAll downloads and extracts every package the plan installs or
+// updates, and checks it against the install policy.
+func (i *Installer) stageAll(ctx context.Context, plan []Action) (stagedPlan, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(i.concurrency)
+
+	staged := stagedPlan{dirs: make([]string, len(plan))}
+	var mu sync.Mutex
+	for idx, action := range plan {
+		if action.Type != ActionInstall && action.Type != ActionUpdate {
+			continue
+		}
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			dir, err := i.stage(ctx, action)
+			if policyErr, ok := errors.AsType[*policy.Error](err); ok {
+				mu.Lock()
+				staged.violations = append(staged.violations, policyErr.Violations...)
+				mu.Unlock()
+				return nil
+			}
+			staged.dirs[idx] = dir
+			return err
+		})
+	}
+	err := g.Wait()
+	return staged, err
+}
+
+// CheckInstalled checks installed packages against the tarball rules of the
+// install policy, as if they were being installed, and returns their
+// violations. A nil policy has none.
+func CheckInstalled(pol *policy.Policy, dirs Dirs, installed []Installed) ([]policy.Violation, error) {
+	if pol == nil {
+		return nil, nil
+	}
+
+	var violations []policy.Violation
+	for _, pkg := range installed {
+		check := pol.Files(pkg.Name, pkg.Version)
+		typeDir, ok := dirs.ForType(pkg.PkgType)
+		if check == nil || !ok {
+			continue
+		}
+		if err := check.Tree(filepath.Join(typeDir, pkg.Name)); err != nil {
+			return nil, fmt.Errorf("failed to check %s@%s against the install policy: %w", pkg.Name, pkg.Version, err)
+		}
+		violations = append(violations, check.Violations()...)
+	}
+	return violations, nil
+}
+
+// install moves a staged package into place, or removes one.
+func (i *Installer) install(ctx context.Context, action Action, stagedDir string) error {
 	targetDir, err := i.getTargetDir(action.PkgType, action.Name)
 	if err != nil {
 		return err
@@ -136,17 +220,24 @@ func (i *Installer) install(ctx context.Context, action Action) error {
 		}
 		return nil
 	case ActionInstall, ActionUpdate:
-		return i.installOrUpdate(ctx, action, targetDir)
+		return i.replaceDir(ctx, stagedDir, targetDir)
 	default:
 		return nil
 	}
 }
 
-func (i *Installer) installOrUpdate(ctx context.Context, action Action, targetDir string) error {
+// stage downloads the package of action into the staging area, checks it,
+// and returns the directory it is extracted in. The staging area is cleared
+// when the Installer is closed.
+func (i *Installer) stage(ctx context.Context, action Action) (string, error) {
+	if _, err := i.getTargetDir(action.PkgType, action.Name); err != nil {
+		return "", err
+	}
+
 	path := tarballPath(action.Name, action.Version)
 	resp, err := i.client.DownloadTarball(ctx, path)
 	if err != nil {
-		return fmt.Errorf("failed to download %s: %w", path, err)
+		return "", fmt.Errorf("failed to download %s: %w", path, err)
 	}
 	defer func() {
 		_ = resp.Close()
@@ -158,18 +249,31 @@ func (i *Installer) installOrUpdate(ctx context.Context, action Action, targetDi
 	select {
 	case i.extractSem <- struct{}{}:
 	case <-ctx.Done():
-		return ctx.Err()
+		return "", ctx.Err()
 	}
 	defer func() { <-i.extractSem }()
 
-	extractedPath, tempContainer, err := i.unpackToStaging(ctx, stream)
-	defer func() {
-		_ = i.removeAll(context.Background(), tempContainer)
-	}()
+	var check *policy.FileCheck
+	if i.policy != nil {
+		check = i.policy.Files(action.Name, action.Version)
+	}
+
+	extractedPath, tempContainer, err := i.unpackToStaging(ctx, stream, check)
 	if err != nil {
-		return fmt.Errorf("failed to unpack package: %w", err)
+		err = fmt.Errorf("failed to unpack package: %w", err)
+	} else {
+		err = verifyStaged(ctx, action, stream, hasher, extractedPath, check)
 	}
+	if err != nil {
+		_ = i.removeAll(context.Background(), tempContainer)
+		return "", err
+	}
+	return extractedPath, nil
+}
 
+// verifyStaged checks the digest of the downloaded tarball and the package
+// extracted from it in dir against the install policy.
+func verifyStaged(ctx context.Context, action Action, stream io.Reader, hasher hash.Hash, dir string, check *policy.FileCheck) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -184,16 +288,27 @@ func (i *Installer) installOrUpdate(ctx context.Context, action Action, targetDi
 		return fmt.Errorf("digest mismatch: expected %s, got %s", cleanDigest, calculated)
 	}
 
-	return i.replaceDir(ctx, extractedPath, targetDir)
+	if check != nil {
+		if err := check.Dir(dir); err != nil {
+			return fmt.Errorf("failed to check %s@%s against the install policy: %w", action.Name, action.Version, err)
+		}
+		if violations := check.Violations(); len(violations) > 0 {
+			return &policy.Error{Violations: violations}
+		}
+	}
+	return nil
 }
 
-func (i *Installer) unpackToStaging(ctx context.Context, r io.Reader) (string, string, error) {
+func (i *Installer) unpackToStaging(ctx context.Context, r io.Reader, check *policy.FileCheck) (string, string, error) {
 	rootTemp, err := os.MkdirTemp(i.runDir, "pkg-*")
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create staging directory: %w", err)
 	}
 
 	opts := &archive.TarOptions{Logger: i.logger}
+	if check != nil {
+		opts.Check = check.Entry
+	}
 	if err := archive.Untar(ctx, r, rootTemp, opts); err != nil {
 		return "", rootTemp, fmt.Errorf("failed to extract tarball: %w", err)
 	}
@@ -353,23 +468,18 @@ func (i *Installer) getTargetDir(pkgType types.PackageType, name string) (string
 		return "", fmt.Errorf("refusing to operate on package with invalid name %q: %w", name, err)
 	}
 
-	var subDir string
-	switch pkgType {
-	case types.TypeTheme:
-		subDir = "themes"
-	case types.TypePlugin:
-		subDir = "plugins"
-	default:
+	typeDir, ok := i.dirs.ForType(pkgType)
+	if !ok {
 		return "", fmt.Errorf("unknown package type %q for package %q", pkgType, name)
 	}
 
-	target := filepath.Join(i.contentDir, subDir, name)
+	target := filepath.Join(typeDir, name)
 
 	// IsValidPackageName already prevents escape, but verify the resolved
-	// path stays inside contentDir in case of symlinks or other weird filesystem setups.
-	rel, err := filepath.Rel(i.contentDir, target)
+	// path stays inside its type directory in case of symlinks or other weird filesystem setups.
+	rel, err := filepath.Rel(typeDir, target)
 	if err != nil || !filepath.IsLocal(rel) {
-		return "", fmt.Errorf("package %q resolves outside content directory", name)
+		return "", fmt.Errorf("package %q resolves outside %s", name, typeDir)
 	}
 
 	return target, nil
@@ -0,0 +1,127 @@
+package installer
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"go.wpm.so/cli/pkg/pm/policy"
+	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+type tarballClient struct {
+	registry.Client
+	tarballs map[string][]byte
+}
+
+func (c *tarballClient) DownloadTarball(_ context.Context, url string) (io.ReadCloser, error) {
+	b, ok := c.tarballs[url]
+	if !ok {
+		return nil, errors.New("not found: " + url)
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func TestInstallAllChecksEverythingFirst(t *testing.T) {
+	root := t.TempDir()
+	dirs := Dirs{
+		Content: root,
+		Plugins: filepath.Join(root, "plugins"),
+		Themes:  filepath.Join(root, "themes"),
+	}
+	legacy := filepath.Join(dirs.Plugins, "legacy")
+	if err := os.MkdirAll(legacy, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "tool.phar"), []byte("<?php"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	akismet := packTarball(t, "akismet", map[string]string{"akismet.php": "<?php"})
+	acme := packTarball(t, "acme", map[string]string{"acme.php": "<?php", "bin/run": "#!/usr/bin/env php"})
+	client := &tarballClient{tarballs: map[string][]byte{
+		"/akismet/5.4.0.tar.zst": akismet,
+		"/acme/1.0.0.tar.zst":    acme,
+	}}
+	plan := []Action{
+		{Type: ActionInstall, Name: "akismet", Version: "5.4.0", Digest: digest(akismet), PkgType: types.TypePlugin},
+		{Type: ActionInstall, Name: "acme", Version: "1.0.0", Digest: digest(acme), PkgType: types.TypePlugin},
+		{Type: ActionRemove, Name: "legacy", PkgType: types.TypePlugin},
+	}
+	installed := []Installed{{Name: "legacy", Version: "0.9.0", PkgType: types.TypePlugin}}
+
+	pol, err := policy.New(&types.Policy{ForbidExecutables: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst, err := New(t.Context(), dirs, 2, client, pol, t.Logf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = inst.Close() }()
+
+	err = inst.InstallAll(t.Context(), plan, installed, nil)
+	policyErr, ok := errors.AsType[*policy.Error](err)
+	if !ok {
+		t.Fatalf("InstallAll() = %v, want a policy error", err)
+	}
+	if len(policyErr.Violations) != 2 {
+		t.Errorf("violations = %+v, want acme's bin/run and legacy's tool.phar", policyErr.Violations)
+	}
+
+	// Neither the clean package nor the removal may have been carried out.
+	if _, err := os.Stat(filepath.Join(dirs.Plugins, "akismet")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("akismet was installed despite the violations: %v", err)
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		t.Errorf("legacy was removed despite the violations: %v", err)
+	}
+
+	if err := inst.InstallAll(t.Context(), plan[:1], nil, nil); err != nil {
+		t.Fatalf("InstallAll() without violations = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dirs.Plugins, "akismet", "akismet.php")); err != nil {
+		t.Errorf("akismet was not installed: %v", err)
+	}
+}
+
+func packTarball(t *testing.T, name string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(zw)
+	for path, content := range files {
+		hdr := &tar.Header{Name: name + "/" + path, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + base64.StdEncoding.EncodeToString(sum[:])
+}
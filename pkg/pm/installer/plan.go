@@ -1,8 +1,10 @@
 package installer
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"go.wpm.so/cli/pkg/pm/resolution"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
@@ -27,11 +29,46 @@ type Action struct {
 	PkgType types.PackageType
 }
 
+// Installed is a package already in place.
+type Installed struct {
+	Name    string
+	Version string
+	PkgType types.PackageType
+}
+
+// Dirs holds the absolute directories packages are installed into.
+type Dirs struct {
+	Content string // Staging happens under Content/.tmp
+	Plugins string
+	Themes  string
+}
+
+// DirsFor returns the install directories configured in wpmCfg, rooted at cwd.
+func DirsFor(cwd string, wpmCfg *wpmjson.Config) Dirs {
+	return Dirs{
+		Content: filepath.Join(cwd, wpmCfg.ContentDir()),
+		Plugins: filepath.Join(cwd, wpmCfg.PluginDir()),
+		Themes:  filepath.Join(cwd, wpmCfg.ThemeDir()),
+	}
+}
+
+// ForType maps a package type to its install directory. Returns false for unknown types.
+func (d Dirs) ForType(t types.PackageType) (string, bool) {
+	switch t {
+	case types.TypeTheme:
+		return d.Themes, true
+	case types.TypePlugin:
+		return d.Plugins, true
+	default:
+		return "", false
+	}
+}
+
 // CalculatePlan determines filesystem operations based on lockfile, resolved tree, and flags.
 func CalculatePlan(
 	lock *wpmlock.Lockfile,
 	resolved map[string]resolution.Node,
-	contentDir string,
+	dirs Dirs,
 	wpmCfg *wpmjson.Config,
 	noDev bool,
 ) []Action {
@@ -46,11 +83,11 @@ func CalculatePlan(
 	for name, node := range resolved {
 		seen[name] = true
 
-		subDir, ok := subDirForType(node.Type)
+		typeDir, ok := dirs.ForType(node.Type)
 		if !ok {
 			continue
 		}
-		exists := pathExists(filepath.Join(contentDir, subDir, name))
+		exists := pathExists(filepath.Join(typeDir, name))
 
 		if noDev && !prodSet[name] {
 			if exists {
@@ -84,16 +121,32 @@ func CalculatePlan(
 	return actions
 }
 
-// subDirForType maps a package type to its content sub-directory. Returns false for unknown types.
-func subDirForType(t types.PackageType) (string, bool) {
-	switch t {
-	case types.TypeTheme:
-		return "themes", true
-	case types.TypePlugin:
-		return "plugins", true
-	default:
-		return "", false
+// Unchanged returns the resolved packages installed in dirs that the plan
+// leaves as they are.
+func Unchanged(plan []Action, resolved map[string]resolution.Node, dirs Dirs) []Installed {
+	planned := make(map[string]bool, len(plan))
+	for _, action := range plan {
+		planned[action.Name] = true
+	}
+
+	var installed []Installed
+	for _, name := range slices.Sorted(maps.Keys(resolved)) {
+		node := resolved[name]
+		if planned[name] || !IsInstalled(dirs, node.Type, name) {
+			continue
+		}
+		installed = append(installed, Installed{Name: name, Version: node.Version, PkgType: node.Type})
+	}
+	return installed
+}
+
+// IsInstalled reports whether a package directory exists in dirs.
+func IsInstalled(dirs Dirs, pkgType types.PackageType, name string) bool {
+	typeDir, ok := dirs.ForType(pkgType)
+	if !ok {
+		return false
 	}
+	return pathExists(filepath.Join(typeDir, name))
 }
 
 func pathExists(p string) bool {
@@ -0,0 +1,157 @@
+package license
+
+import (
+	"errors"
+	"strings"
+)
+
+// node is a parsed SPDX license expression.
+type node struct {
+	op          string // "AND", "OR", or "" for a license
+	left, right *node
+	id          string
+	exception   string // the part after WITH
+}
+
+// parse parses an SPDX license expression. AND binds tighter than OR, and
+// operators may be written in any case.
+func parse(expr string) (*node, error) {
+	p := &parser{tokens: tokenize(expr)}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, errors.New("unexpected " + p.tokens[p.pos])
+	}
+	return n, nil
+}
+
+func tokenize(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	return strings.Fields(expr)
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) or() (*node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &node{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (*node, error) {
+	left, err := p.license()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.next()
+		right, err := p.license()
+		if err != nil {
+			return nil, err
+		}
+		left = &node{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) license() (*node, error) {
+	switch t := p.next(); {
+	case t == "":
+		return nil, errors.New("unexpected end of expression")
+	case t == "(":
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+		return n, nil
+	case t == ")" || isOperator(t):
+		return nil, errors.New("unexpected " + t)
+	default:
+		n := &node{id: t}
+		if strings.EqualFold(p.peek(), "WITH") {
+			p.next()
+			if n.exception = p.next(); n.exception == "" || isOperator(n.exception) {
+				return nil, errors.New("missing exception after WITH")
+			}
+		}
+		return n, nil
+	}
+}
+
+func isOperator(t string) bool {
+	return strings.EqualFold(t, "AND") || strings.EqualFold(t, "OR") || strings.EqualFold(t, "WITH")
+}
+
+// walk calls fn with each license identifier in the expression.
+func (n *node) walk(fn func(id *string)) {
+	if n.op == "" {
+		fn(&n.id)
+		return
+	}
+	n.left.walk(fn)
+	n.right.walk(fn)
+}
+
+// eval reports whether the expression is satisfied when allowed says which
+// licenses are acceptable. Exceptions only widen what a license permits, so
+// they don't affect the outcome.
+func (n *node) eval(allowed func(id string) bool) bool {
+	switch n.op {
+	case "AND":
+		return n.left.eval(allowed) && n.right.eval(allowed)
+	case "OR":
+		return n.left.eval(allowed) || n.right.eval(allowed)
+	default:
+		return allowed(n.id)
+	}
+}
+
+func (n *node) String() string {
+	if n.op == "" {
+		if n.exception != "" {
+			return n.id + " WITH " + n.exception
+		}
+		return n.id
+	}
+	return n.operand(n.left) + " " + n.op + " " + n.operand(n.right)
+}
+
+// operand formats a child, adding parentheses where the operator changes so
+// that the grouping reads the same without knowing the precedence.
+func (n *node) operand(child *node) string {
+	if child.op != "" && child.op != n.op {
+		return "(" + child.String() + ")"
+	}
+	return child.String()
+}
@@ -0,0 +1,140 @@
+// Package license normalizes package licenses to SPDX identifiers and checks
+// them against a project's license policy.
+package license
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Unknown stands for a package that doesn't declare a license.
+const Unknown = "UNKNOWN"
+
+// known maps license names, lowercased and stripped of everything but letters,
+// digits and "+", to their SPDX identifiers. Deprecated identifiers such as
+// "GPL-2.0+" map to their current form.
+var known = map[string]string{
+	"gpl20orlater": "GPL-2.0-or-later", "gplv2orlater": "GPL-2.0-or-later", "gpl2orlater": "GPL-2.0-or-later",
+	"gpl20+": "GPL-2.0-or-later", "gplv2+": "GPL-2.0-or-later", "gpl2+": "GPL-2.0-or-later",
+	"gnugplv2orlater": "GPL-2.0-or-later", "gnugeneralpubliclicensev2orlater": "GPL-2.0-or-later",
+	"gplversion2orlater": "GPL-2.0-or-later", "gplv2orlaterlicense": "GPL-2.0-or-later",
+	"gpl20only": "GPL-2.0-only", "gpl20": "GPL-2.0-only", "gplv2": "GPL-2.0-only", "gpl2": "GPL-2.0-only",
+	"gplv20": "GPL-2.0-only", "gnugplv2": "GPL-2.0-only", "gplversion2": "GPL-2.0-only",
+	"gpl30orlater": "GPL-3.0-or-later", "gplv3orlater": "GPL-3.0-or-later", "gpl3orlater": "GPL-3.0-or-later",
+	"gpl30+": "GPL-3.0-or-later", "gplv3+": "GPL-3.0-or-later", "gpl3+": "GPL-3.0-or-later",
+	"gnugplv3orlater": "GPL-3.0-or-later", "gplversion3orlater": "GPL-3.0-or-later",
+	"gpl30only": "GPL-3.0-only", "gpl30": "GPL-3.0-only", "gplv3": "GPL-3.0-only", "gpl3": "GPL-3.0-only",
+	"gplv30": "GPL-3.0-only", "gnugplv3": "GPL-3.0-only", "gplversion3": "GPL-3.0-only",
+	"lgpl21orlater": "LGPL-2.1-or-later", "lgplv21orlater": "LGPL-2.1-or-later", "lgpl21+": "LGPL-2.1-or-later", "lgplv21+": "LGPL-2.1-or-later",
+	"lgpl21only": "LGPL-2.1-only", "lgpl21": "LGPL-2.1-only", "lgplv21": "LGPL-2.1-only",
+	"lgpl30orlater": "LGPL-3.0-or-later", "lgplv3orlater": "LGPL-3.0-or-later", "lgpl30+": "LGPL-3.0-or-later", "lgplv3+": "LGPL-3.0-or-later",
+	"lgpl30only": "LGPL-3.0-only", "lgpl30": "LGPL-3.0-only", "lgplv3": "LGPL-3.0-only", "lgpl3": "LGPL-3.0-only",
+	"agpl30orlater": "AGPL-3.0-or-later", "agplv3orlater": "AGPL-3.0-or-later", "agpl30+": "AGPL-3.0-or-later",
+	"agpl30only": "AGPL-3.0-only", "agpl30": "AGPL-3.0-only", "agplv3": "AGPL-3.0-only", "agpl3": "AGPL-3.0-only",
+	"mit": "MIT", "mitlicense": "MIT", "expat": "MIT",
+	"apache20": "Apache-2.0", "apache2": "Apache-2.0", "apachev2": "Apache-2.0", "apachelicense20": "Apache-2.0",
+	"apachelicensev2": "Apache-2.0", "apachelicense2": "Apache-2.0", "apachelicenseversion20": "Apache-2.0",
+	"bsd3clause": "BSD-3-Clause", "bsd3": "BSD-3-Clause", "newbsd": "BSD-3-Clause", "modifiedbsd": "BSD-3-Clause",
+	"bsd2clause": "BSD-2-Clause", "bsd2": "BSD-2-Clause", "simplifiedbsd": "BSD-2-Clause", "freebsd": "BSD-2-Clause",
+	"mpl20": "MPL-2.0", "mozillapubliclicense20": "MPL-2.0",
+	"isc": "ISC", "isclicense": "ISC",
+	"unlicense": "Unlicense", "theunlicense": "Unlicense",
+	"cc010": "CC0-1.0", "cc0": "CC0-1.0",
+	"ccby40": "CC-BY-4.0", "ccbysa40": "CC-BY-SA-4.0",
+	"artistic20": "Artistic-2.0", "zlib": "Zlib", "wtfpl": "WTFPL", "0bsd": "0BSD",
+}
+
+// Normalize returns the SPDX form of a license: an identifier, or an
+// expression such as "MIT OR GPL-2.0-or-later" when every part of it is
+// recognized. It returns Unknown for an empty license, and the trimmed text
+// with ok false for anything it doesn't recognize.
+func Normalize(license string) (normalized string, ok bool) {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return Unknown, false
+	}
+	if id, found := known[key(license)]; found {
+		return id, true
+	}
+
+	node, err := parse(license)
+	if err != nil {
+		return license, false
+	}
+	ok = true
+	node.walk(func(id *string) {
+		if canonical, found := known[key(*id)]; found {
+			*id = canonical
+		} else {
+			ok = false
+		}
+	})
+	if !ok {
+		return license, false
+	}
+	return node.String(), true
+}
+
+func key(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r == '+' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Policy is a project's allow and deny lists. Entries are license
+// identifiers, normalized like package licenses, or patterns such as "GPL-*".
+type Policy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Check returns an error if license violates the policy. With an allow list,
+// a license must be on it; a license on the deny list is always refused. For
+// an expression, "OR" is satisfied by either side and "AND" needs both.
+func (p Policy) Check(license string) error {
+	normalized, _ := Normalize(license)
+
+	var allowed bool
+	if node, err := parse(normalized); err == nil && normalized != Unknown {
+		allowed = node.eval(p.allows)
+	} else {
+		allowed = p.allows(normalized)
+	}
+	if allowed {
+		return nil
+	}
+
+	if license == "" {
+		return errors.New("no license is declared, which the license policy in wpm.json doesn't allow")
+	}
+	return fmt.Errorf("license %q is not allowed by the license policy in wpm.json", normalized)
+}
+
+func (p Policy) allows(id string) bool {
+	if matchAny(p.Deny, id) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchAny(p.Allow, id)
+}
+
+func matchAny(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if normalized, ok := Normalize(pattern); ok {
+			pattern = normalized
+		}
+		if strings.EqualFold(pattern, id) {
+			return true
+		}
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(id)); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package license
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"GPLv2 or later", "GPL-2.0-or-later", true},
+		{"GPL-2.0+", "GPL-2.0-or-later", true},
+		{"gpl-3.0", "GPL-3.0-only", true},
+		{"MIT License", "MIT", true},
+		{"mit or gplv2+", "MIT OR GPL-2.0-or-later", true},
+		{"(MIT AND apache-2.0) OR GPL-3.0-or-later", "(MIT AND Apache-2.0) OR GPL-3.0-or-later", true},
+		{"", Unknown, false},
+		{"  ", Unknown, false},
+		{"Proprietary", "Proprietary", false},
+		{"MIT OR Proprietary", "MIT OR Proprietary", false},
+	}
+	for _, tt := range tests {
+		got, ok := Normalize(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Normalize(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		license string
+		wantErr bool
+	}{
+		{"no policy", Policy{}, "Proprietary", false},
+		{"allowed", Policy{Allow: []string{"GPL-2.0-or-later", "MIT"}}, "GPLv2 or later", false},
+		{"not allowed", Policy{Allow: []string{"MIT"}}, "GPL-3.0", true},
+		{"denied", Policy{Deny: []string{"AGPL-3.0-only"}}, "AGPLv3", true},
+		{"deny wins", Policy{Allow: []string{"GPL-*"}, Deny: []string{"GPL-3.0-only"}}, "GPL-3.0", true},
+		{"glob", Policy{Allow: []string{"GPL-*"}}, "GPL-3.0-or-later", false},
+		{"allow written loosely", Policy{Allow: []string{"GPLv2+"}}, "GPL-2.0-or-later", false},
+		{"OR either side", Policy{Allow: []string{"MIT"}}, "MIT OR GPL-3.0", false},
+		{"AND both sides", Policy{Allow: []string{"MIT"}}, "MIT AND GPL-3.0", true},
+		{"OR with denied side", Policy{Deny: []string{"GPL-3.0-only"}}, "MIT OR GPL-3.0", false},
+		{"unknown refused by allow list", Policy{Allow: []string{"MIT"}}, "", true},
+		{"unknown allowed", Policy{Allow: []string{"MIT", Unknown}}, "", false},
+		{"unknown denied", Policy{Deny: []string{Unknown}}, "", true},
+		{"unrecognized text", Policy{Allow: []string{"Proprietary"}}, "proprietary", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.license)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, wantErr %v", tt.license, err, tt.wantErr)
+			}
+		})
+	}
+}
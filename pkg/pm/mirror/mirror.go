@@ -0,0 +1,123 @@
+// Package mirror fetches release tarballs from user-configured mirrors.
+//
+// A mirror serves each tarball at a URL and its expected digest in a sidecar
+// file at the same URL plus ".sha256", in either sha256sum(1) hex form or
+// wpm's "sha256:<base64>" form. Both http(s) and file URLs are supported.
+package mirror
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	checksumSuffix  = ".sha256"
+	maxChecksumSize = 4096
+)
+
+// ErrNotFound is returned when the mirror has no file at the requested URL.
+var ErrNotFound = errors.New("not found on mirror")
+
+// Expand replaces each {key} placeholder in template with its value.
+func Expand(template string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for k, v := range values {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// Fetch opens rawURL for reading. The caller must close the reader.
+func Fetch(ctx context.Context, client *http.Client, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "file" {
+		f, err := os.Open(u.Path) //nolint:gosec // the mirror location is configured by the project owner
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return f, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Digest reads the checksum sidecar for tarballURL and returns it as
+// "sha256:<base64>".
+func Digest(ctx context.Context, client *http.Client, tarballURL string) (string, error) {
+	rc, err := Fetch(ctx, client, tarballURL+checksumSuffix)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+
+	digest, err := parseChecksum(io.LimitReader(rc, maxChecksumSize))
+	if err != nil {
+		return "", fmt.Errorf("invalid checksum for %s: %w", tarballURL, err)
+	}
+	return digest, nil
+}
+
+// parseChecksum reads the first field of a checksum file and returns it as
+// "sha256:<base64>".
+func parseChecksum(r io.Reader) (string, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", errors.New("checksum file is empty")
+	}
+
+	fields := strings.Fields(scanner.Text())
+	if len(fields) == 0 {
+		return "", errors.New("checksum file is empty")
+	}
+	sum := fields[0]
+
+	if b64, ok := strings.CutPrefix(sum, "sha256:"); ok {
+		raw, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil || len(raw) != 32 {
+			return "", errors.New("malformed sha256 digest")
+		}
+		return sum, nil
+	}
+
+	raw, err := hex.DecodeString(sum)
+	if err != nil || len(raw) != 32 {
+		return "", errors.New("malformed sha256 digest")
+	}
+	return "sha256:" + base64.StdEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,35 @@
+package mirror
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseChecksum(t *testing.T) {
+	const hexSum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	const b64Sum = "sha256:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"hex", hexSum + "\n", b64Sum, false},
+		{"sha256sum", hexSum + "  wordpress-6.9.0.tar.zst\n", b64Sum, false},
+		{"base64", b64Sum, b64Sum, false},
+		{"empty", "", "", true},
+		{"short", "abcd", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseChecksum(strings.NewReader(tc.in))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseChecksum err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("parseChecksum = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
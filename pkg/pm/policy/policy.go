@@ -0,0 +1,354 @@
+// Package policy enforces the install policy a project sets in wpm.json:
+// rules on the packages' types and signatures, checked while resolving, and
+// on the contents of their tarballs, checked while extracting them.
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/go-units"
+
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+// Rules, named after their keys in wpm.json.
+const (
+	RuleMaxUnpackedSize     = "max-unpacked-size"
+	RuleForbiddenFiles      = "forbidden-files"
+	RuleForbidExecutables   = "forbid-executables"
+	RuleSignedBy            = "signed-by"
+	RuleNoDependencyScripts = "no-dependency-scripts"
+	RuleAllowedTypes        = "allowed-types"
+)
+
+// lifecycleScripts are the scripts a package manager runs for a dependency
+// when installing or removing it.
+var lifecycleScripts = []string{"preinstall", "install", "postinstall", "preuninstall", "uninstall", "postuninstall"}
+
+// executableExts are the extensions of files meant to be run rather than
+// loaded by PHP. The packer stores every file as 0644, so the mode bits
+// can't tell.
+var executableExts = []string{".phar", ".exe", ".dll", ".so", ".dylib"}
+
+// binaryMagic are the leading bytes of native binaries: ELF, PE, and Mach-O
+// in both byte orders, 32 and 64 bit, and universal.
+var binaryMagic = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce},
+	{0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe},
+	{0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe},
+}
+
+// headSize is how much of a file is read to tell whether it's executable.
+// The headers of the binaries in binaryMagic all have a NUL byte in it,
+// which text that happens to start like one doesn't.
+const headSize = 64
+
+// Policy is a project's compiled install policy.
+type Policy struct {
+	maxUnpackedSize     int64
+	forbiddenFiles      []string
+	forbidExecutables   bool
+	signedBy            []string
+	noDependencyScripts bool
+	allowedTypes        []types.PackageType
+}
+
+// New compiles the install policy from wpm.json. It returns nil if cfg sets
+// no rules.
+func New(cfg *types.Policy) (*Policy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	p := &Policy{
+		forbiddenFiles:      cfg.ForbiddenFiles,
+		forbidExecutables:   cfg.ForbidExecutables,
+		signedBy:            cfg.SignedBy,
+		noDependencyScripts: cfg.NoDependencyScripts,
+		allowedTypes:        cfg.AllowedTypes,
+	}
+	if cfg.MaxUnpackedSize != "" {
+		size, err := units.FromHumanSize(cfg.MaxUnpackedSize)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid config.policy.%s %q: expected a size such as \"20MB\"", RuleMaxUnpackedSize, cfg.MaxUnpackedSize)
+		}
+		p.maxUnpackedSize = size
+	}
+	for _, pattern := range p.forbiddenFiles {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid config.policy.%s pattern %q: %w", RuleForbiddenFiles, pattern, err)
+		}
+	}
+	for _, t := range p.allowedTypes {
+		if !t.Valid() {
+			return nil, fmt.Errorf("invalid config.policy.%s entry %q: must be theme or plugin", RuleAllowedTypes, t)
+		}
+	}
+
+	if p.maxUnpackedSize == 0 && len(p.forbiddenFiles) == 0 && !p.forbidExecutables &&
+		len(p.signedBy) == 0 && !p.noDependencyScripts && len(p.allowedTypes) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// CheckPackage checks the rules that apply to a package's manifest. signedBy
+// is the IDs of the keys that validly signed it.
+func (p *Policy) CheckPackage(m *manifest.Package, signedBy []string) []Violation {
+	var violations []Violation
+	add := func(rule, reason string) {
+		violations = append(violations, Violation{Package: m.Name, Version: m.Version, Rule: rule, Reason: reason})
+	}
+
+	if len(p.allowedTypes) > 0 && !slices.Contains(p.allowedTypes, m.Type) {
+		add(RuleAllowedTypes, fmt.Sprintf("package type %q is not allowed", m.Type))
+	}
+	if p.maxUnpackedSize > 0 && m.Dist.UnpackedSize > p.maxUnpackedSize {
+		add(RuleMaxUnpackedSize, "unpacks to more than "+units.HumanSize(float64(p.maxUnpackedSize)))
+	}
+	if len(p.signedBy) > 0 && !slices.ContainsFunc(signedBy, func(id string) bool { return slices.Contains(p.signedBy, id) }) {
+		add(RuleSignedBy, "not signed by any of the required keys")
+	}
+	return violations
+}
+
+// Files returns a check of a package's tarball, or nil if the policy has no
+// rules on tarballs.
+func (p *Policy) Files(name, version string) *FileCheck {
+	if p.maxUnpackedSize == 0 && len(p.forbiddenFiles) == 0 && !p.forbidExecutables && !p.noDependencyScripts {
+		return nil
+	}
+	return &FileCheck{policy: p, name: name, version: version}
+}
+
+// FileCheck collects the violations in one package's tarball.
+type FileCheck struct {
+	policy     *Policy
+	name       string
+	version    string
+	size       int64
+	violations []Violation
+}
+
+// Entry checks a tarball entry. It is meant as archive.TarOptions.Check.
+// It only stops the extraction, with an *Error, once the package unpacks to
+// more than max-unpacked-size; other violations are collected so that every
+// one gets reported.
+func (c *FileCheck) Entry(hdr *tar.Header) error {
+	// Entries sit in the package's root directory.
+	_, rel, ok := strings.Cut(filepath.ToSlash(hdr.Name), "/")
+	if !ok {
+		return nil
+	}
+
+	if hdr.Typeflag != tar.TypeDir {
+		for _, pattern := range c.policy.forbiddenFiles {
+			if matchFile(pattern, rel) {
+				c.add(RuleForbiddenFiles, fmt.Sprintf("%s matches %q", rel, pattern))
+				break
+			}
+		}
+	}
+
+	if hdr.Typeflag == tar.TypeReg {
+		c.size += hdr.Size
+		if limit := c.policy.maxUnpackedSize; limit > 0 && c.size > limit {
+			c.add(RuleMaxUnpackedSize, "unpacks to more than "+units.HumanSize(float64(limit)))
+			return &Error{Violations: slices.Clone(c.violations)}
+		}
+	}
+	return nil
+}
+
+// Dir checks the extracted package in dir.
+func (c *FileCheck) Dir(dir string) error {
+	if c.policy.forbidExecutables {
+		if err := c.executables(dir); err != nil {
+			return err
+		}
+	}
+	if !c.policy.noDependencyScripts {
+		return nil
+	}
+
+	config, err := wpmjson.Read(dir)
+	if err != nil {
+		return err
+	}
+	if config == nil || config.Scripts == nil {
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(*config.Scripts)) {
+		if slices.Contains(lifecycleScripts, name) {
+			c.add(RuleNoDependencyScripts, fmt.Sprintf("wpm.json declares a %q script", name))
+		}
+	}
+	return nil
+}
+
+// Tree checks a package installed in dir: its files as Entry checks those of
+// a tarball, then the package as Dir does.
+func (c *FileCheck) Tree(dir string) error {
+	name := filepath.Base(dir)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = name + "/" + filepath.ToSlash(rel)
+		return c.Entry(hdr)
+	})
+	if _, ok := errors.AsType[*Error](err); !ok && err != nil {
+		return err
+	}
+	return c.Dir(dir)
+}
+
+// executables flags the files in dir that are native binaries or scripts,
+// by their extension or their first bytes.
+func (c *FileCheck) executables(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if slices.Contains(executableExts, strings.ToLower(path.Ext(rel))) {
+			c.add(RuleForbidExecutables, rel+" is executable")
+			return nil
+		}
+		head, err := readHead(p, headSize)
+		if err != nil {
+			return err
+		}
+		if isExecutable(head) {
+			c.add(RuleForbidExecutables, rel+" is executable")
+		}
+		return nil
+	})
+}
+
+// isExecutable reports whether a file starting with head is a script with a
+// shebang line or a native binary.
+func isExecutable(head []byte) bool {
+	if bytes.HasPrefix(head, []byte("#!")) {
+		return true
+	}
+	return bytes.IndexByte(head, 0) >= 0 &&
+		slices.ContainsFunc(binaryMagic, func(magic []byte) bool { return bytes.HasPrefix(head, magic) })
+}
+
+// readHead returns up to the first n bytes of the file at name.
+func readHead(name string, n int) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	head := make([]byte, n)
+	read, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return head[:read], nil
+}
+
+// Violations returns the violations found so far.
+func (c *FileCheck) Violations() []Violation {
+	return c.violations
+}
+
+func (c *FileCheck) add(rule, reason string) {
+	c.violations = append(c.violations, Violation{Package: c.name, Version: c.version, Rule: rule, Reason: reason})
+}
+
+// matchFile reports whether pattern matches the file at rel, a slash
+// separated path inside the package. A pattern without a "/" is matched
+// against the file name; one with a "/" against the path or a directory
+// on it.
+func matchFile(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(rel))
+		return matched
+	}
+
+	pattern = strings.Trim(pattern, "/")
+	for p := rel; p != "."; p = path.Dir(p) {
+		if matched, _ := path.Match(pattern, p); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation is a package breaking a rule of the install policy.
+type Violation struct {
+	Package string
+	Version string
+	Rule    string
+	Reason  string
+}
+
+// Error reports every violation of the install policy.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	violations := slices.Clone(e.Violations)
+	slices.SortStableFunc(violations, func(a, b Violation) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+
+	packages := make(map[string]bool)
+	for _, v := range violations {
+		packages[v.Package] = true
+	}
+	noun := "packages"
+	if len(packages) == 1 {
+		noun = "package"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "install policy in wpm.json violated by %d %s:", len(packages), noun)
+	for _, v := range violations {
+		fmt.Fprintf(&b, "\n  %s@%s: %s (%s)", output.Sanitize(v.Package), output.Sanitize(v.Version), output.Sanitize(v.Reason), v.Rule)
+	}
+	return b.String()
+}
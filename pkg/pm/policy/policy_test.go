@@ -0,0 +1,249 @@
+package policy
+
+import (
+	"archive/tar"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *types.Policy
+		wantNil bool
+		wantErr string
+	}{
+		{name: "unset", cfg: nil, wantNil: true},
+		{name: "empty", cfg: &types.Policy{}, wantNil: true},
+		{name: "size", cfg: &types.Policy{MaxUnpackedSize: "20MB"}},
+		{name: "bad size", cfg: &types.Policy{MaxUnpackedSize: "lots"}, wantErr: "invalid config.policy.max-unpacked-size"},
+		{name: "bad pattern", cfg: &types.Policy{ForbiddenFiles: []string{"[a-"}}, wantErr: "invalid config.policy.forbidden-files"},
+		{name: "bad type", cfg: &types.Policy{AllowedTypes: []types.PackageType{"mu-plugin"}}, wantErr: "invalid config.policy.allowed-types"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("New() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if (p == nil) != tt.wantNil {
+				t.Errorf("New() = %v, want nil %v", p, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestCheckPackage(t *testing.T) {
+	p, err := New(&types.Policy{
+		MaxUnpackedSize: "20MB",
+		AllowedTypes:    []types.PackageType{types.TypePlugin},
+		SignedBy:        []string{"SHA256:registry", "SHA256:author"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	theme := &manifest.Package{Name: "astra", Version: "4.8.0", Type: types.TypeTheme}
+	plugin := &manifest.Package{Name: "akismet", Version: "5.4.0", Type: types.TypePlugin}
+	large := &manifest.Package{Name: "jetpack", Version: "14.0.0", Type: types.TypePlugin, Dist: manifest.Dist{UnpackedSize: 30_000_000}}
+
+	if got := rules(p.CheckPackage(plugin, []string{"SHA256:author"})); len(got) != 0 {
+		t.Errorf("allowed plugin violates %v", got)
+	}
+	if got, want := rules(p.CheckPackage(theme, []string{"SHA256:other"})), []string{RuleAllowedTypes, RuleSignedBy}; !slices.Equal(got, want) {
+		t.Errorf("CheckPackage(theme) = %v, want %v", got, want)
+	}
+	if got, want := rules(p.CheckPackage(large, []string{"SHA256:registry"})), []string{RuleMaxUnpackedSize}; !slices.Equal(got, want) {
+		t.Errorf("CheckPackage(large) = %v, want %v", got, want)
+	}
+}
+
+func TestFileCheck(t *testing.T) {
+	p, err := New(&types.Policy{
+		MaxUnpackedSize: "1kB",
+		ForbiddenFiles:  []string{"*.phar", "vendor/bin"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := p.Files("akismet", "5.4.0")
+	entries := []*tar.Header{
+		{Name: "akismet", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "akismet/akismet.php", Typeflag: tar.TypeReg, Mode: 0o644, Size: 600},
+		{Name: "akismet/lib/tool.phar", Typeflag: tar.TypeReg, Mode: 0o644, Size: 100},
+		{Name: "akismet/vendor/bin/run", Typeflag: tar.TypeReg, Mode: 0o644, Size: 100},
+		{Name: "akismet/readme.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 600},
+	}
+	for _, hdr := range entries[:len(entries)-1] {
+		if err := check.Entry(hdr); err != nil {
+			t.Fatalf("Entry(%s) = %v", hdr.Name, err)
+		}
+	}
+
+	// Passing the size limit stops the extraction.
+	err = check.Entry(entries[len(entries)-1])
+	if _, ok := errors.AsType[*Error](err); !ok {
+		t.Fatalf("Entry() past max-unpacked-size = %v, want *Error", err)
+	}
+
+	want := []string{
+		`lib/tool.phar matches "*.phar"`,
+		`vendor/bin/run matches "vendor/bin"`,
+		"unpacks to more than 1kB",
+	}
+	if got := reasons(check.Violations()); !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+}
+
+func TestFileCheckExecutables(t *testing.T) {
+	p, err := New(&types.Policy{ForbidExecutables: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"akismet.php":    "<?php\n/* Plugin Name: Akismet */",
+		"readme.txt":     "MZ is not a magic number in a readme",
+		"bin/acme.phar":  "<?php __HALT_COMPILER();",
+		"lib/helper.SO":  "",
+		"vendor/bin/run": "#!/usr/bin/env php\n<?php",
+		"tools/linux":    "\x7fELF\x02\x01\x01\x00",
+		"tools/mac":      "\xcf\xfa\xed\xfe\x07\x00\x00\x01",
+		"tools/win":      "MZ\x90\x00\x03\x00",
+	})
+
+	check := p.Files("akismet", "5.4.0")
+	if err := check.Dir(dir); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"bin/acme.phar is executable",
+		"lib/helper.SO is executable",
+		"tools/linux is executable",
+		"tools/mac is executable",
+		"tools/win is executable",
+		"vendor/bin/run is executable",
+	}
+	if got := reasons(check.Violations()); !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+}
+
+func TestFileCheckTree(t *testing.T) {
+	p, err := New(&types.Policy{
+		MaxUnpackedSize:   "10B",
+		ForbiddenFiles:    []string{"*.log"},
+		ForbidExecutables: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "akismet")
+	writeFiles(t, dir, map[string]string{
+		"akismet.php":  "<?php",
+		"debug.log":    "",
+		"vendor/bin/x": "#!/bin/sh\n",
+		"z.txt":        "more than ten bytes",
+	})
+
+	check := p.Files("akismet", "5.4.0")
+	if err := check.Tree(dir); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`debug.log matches "*.log"`,
+		"unpacks to more than 10B",
+		"vendor/bin/x is executable",
+	}
+	if got := reasons(check.Violations()); !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+}
+
+func TestFileCheckDependencyScripts(t *testing.T) {
+	p, err := New(&types.Policy{NoDependencyScripts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	config := `{"name": "akismet", "scripts": {"build": "make", "postinstall": "php setup.php"}}`
+	if err := os.WriteFile(filepath.Join(dir, "wpm.json"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := p.Files("akismet", "5.4.0")
+	if err := check.Dir(dir); err != nil {
+		t.Fatal(err)
+	}
+	violations := check.Violations()
+	if len(violations) != 1 || violations[0].Reason != `wpm.json declares a "postinstall" script` {
+		t.Errorf("violations = %+v, want one for postinstall", violations)
+	}
+
+	if err := p.Files("hello-dolly", "1.7.2").Dir(t.TempDir()); err != nil {
+		t.Errorf("Dir() without wpm.json = %v", err)
+	}
+}
+
+func TestError(t *testing.T) {
+	err := &Error{Violations: []Violation{
+		{Package: "zeta", Version: "1.0.0", Rule: RuleAllowedTypes, Reason: `package type "theme" is not allowed`},
+		{Package: "akismet", Version: "5.4.0", Rule: RuleForbiddenFiles, Reason: `tool.phar matches "*.phar"`},
+		{Package: "akismet", Version: "5.4.0", Rule: RuleForbidExecutables, Reason: "run is executable"},
+	}}
+
+	want := `install policy in wpm.json violated by 2 packages:
+  akismet@5.4.0: tool.phar matches "*.phar" (forbidden-files)
+  akismet@5.4.0: run is executable (forbid-executables)
+  zeta@1.0.0: package type "theme" is not allowed (allowed-types)`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func reasons(violations []Violation) []string {
+	var out []string
+	for _, v := range violations {
+		out = append(out, v.Reason)
+	}
+	return out
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func rules(violations []Violation) []string {
+	var out []string
+	for _, v := range violations {
+		out = append(out, v.Rule)
+	}
+	return out
+}
@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"go.wpm.so/cli/pkg/api"
+	"go.wpm.so/cli/pkg/pm/advisory"
 	"go.wpm.so/cli/pkg/pm/signatures"
 	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 )
 
 const (
@@ -32,7 +36,11 @@ type Client interface {
 	DownloadTarball(ctx context.Context, url string) (io.ReadCloser, error)
 	PutPackage(ctx context.Context, data *manifest.Package, tarball io.Reader) error
 	GetPackageManifest(ctx context.Context, packageName, versionOrTag string, force bool) (*manifest.Package, error)
+	GetPackument(ctx context.Context, packageName string) (*manifest.Packument, error)
 	AddDistTag(ctx context.Context, packageName, tag, version string) error
+	GetTranslation(ctx context.Context, packageName, version, locale string) (*manifest.Translation, error)
+	Search(ctx context.Context, opts SearchOptions) (*SearchResult, error)
+	GetAdvisories(ctx context.Context, packages []string) (*advisory.Database, error)
 }
 
 var _ Client = &client{}
@@ -141,6 +149,46 @@ func (c *client) GetPackageManifest(ctx context.Context, packageName, versionOrT
 	return pkg, nil
 }
 
+// GetPackument retrieves every published version of a package from the registry
+func (c *client) GetPackument(ctx context.Context, packageName string) (*manifest.Packument, error) {
+	var p *manifest.Packument
+
+	err := c.restClient.DoWithContext(
+		ctx,
+		http.MethodGet,
+		"/"+packageName,
+		nil,
+		&p,
+		api.WithHeader(api.HeaderCacheRevalidate, "true"), // Used by cache round tripper.
+		api.WithHeader(api.HeaderAccept, wpmContentTypeManifestV1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetTranslation retrieves the translation pack manifest for a package version and locale
+func (c *client) GetTranslation(ctx context.Context, packageName, version, locale string) (*manifest.Translation, error) {
+	var t *manifest.Translation
+
+	err := c.restClient.DoWithContext(
+		ctx,
+		http.MethodGet,
+		"/"+packageName+"/"+version+"/translations/"+locale,
+		nil,
+		&t,
+		api.WithHeader(api.HeaderSaveCache, "true"), // Used by cache round tripper.
+		api.WithHeader(api.HeaderAccept, wpmContentTypeManifestV1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
 // DownloadTarball downloads a package tarball from the registry
 func (c *client) DownloadTarball(ctx context.Context, url string) (io.ReadCloser, error) {
 	return c.restClient.RequestStream(
@@ -186,3 +234,109 @@ func (c *client) GetKeysJson(ctx context.Context) (signatures.Keys, error) {
 
 	return keys, nil
 }
+
+// Search sort orders.
+const (
+	SortRelevance = "relevance"
+	SortDownloads = "downloads"
+)
+
+// SearchOptions narrows a registry search. Empty fields don't filter.
+type SearchOptions struct {
+	Text        string
+	Type        string
+	Tag         string
+	RequiresWP  string // a WordPress version the package must support
+	RequiresPHP string // a PHP version the package must support
+	License     string
+	Sort        string // SortRelevance or SortDownloads
+	Page        int    // 1-based
+	PerPage     int
+}
+
+// SearchResult is one page of search results.
+type SearchResult struct {
+	Total    int             `json:"total"`
+	Page     int             `json:"page"`
+	PerPage  int             `json:"perPage"`
+	Packages []SearchPackage `json:"packages"`
+}
+
+// SearchPackage is the latest release of a package matching a search.
+type SearchPackage struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description,omitempty"`
+	Type        string          `json:"type"`
+	License     string          `json:"license,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Requires    *types.Requires `json:"requires,omitempty"`
+	Downloads   int64           `json:"downloads"`
+}
+
+// Search looks up packages in the registry
+func (c *client) Search(ctx context.Context, opts SearchOptions) (*SearchResult, error) {
+	var result *SearchResult
+
+	query := url.Values{}
+	query.Set("text", opts.Text)
+	for key, value := range map[string]string{
+		"type":        opts.Type,
+		"tag":         opts.Tag,
+		"requiresWp":  opts.RequiresWP,
+		"requiresPhp": opts.RequiresPHP,
+		"license":     opts.License,
+		"sort":        opts.Sort,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PerPage > 0 {
+		query.Set("perPage", strconv.Itoa(opts.PerPage))
+	}
+
+	err := c.restClient.DoWithContext(
+		ctx,
+		http.MethodGet,
+		"/-/search",
+		nil,
+		&result,
+		api.WithQuery(query),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type advisoriesRequest struct {
+	Packages []string `json:"packages"`
+}
+
+// GetAdvisories retrieves every security advisory for the given packages
+func (c *client) GetAdvisories(ctx context.Context, packages []string) (*advisory.Database, error) {
+	var db *advisory.Database
+
+	body, err := json.Marshal(advisoriesRequest{Packages: packages})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.restClient.DoWithContext(
+		ctx,
+		http.MethodPost,
+		"/-/advisories/bulk",
+		bytes.NewReader(body),
+		&db,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
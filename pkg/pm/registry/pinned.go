@@ -0,0 +1,99 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/signatures"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+type pinnedKeysClient struct {
+	Client
+	path string
+	warn io.Writer
+
+	mu   sync.Mutex
+	keys signatures.Keys
+}
+
+// WithPinnedKeys returns a client whose signing keys are pinned in the file
+// at path. The keys are trusted on first use; when the registry later serves
+// a different set, a warning is written to warn and the new set is pinned. A
+// key whose public key was swapped is refused.
+func WithPinnedKeys(c Client, path string, warn io.Writer) Client {
+	return &pinnedKeysClient{Client: c, path: path, warn: warn}
+}
+
+// KeysPinFile returns the name of the file the keys of the registry at host
+// are pinned in.
+func KeysPinFile(host string) string {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return unsafeFileChars.ReplaceAllString(host, "_") + ".json"
+}
+
+// GetKeysJson retrieves the public keys from the registry and checks them
+// against the pinned ones. The keys are fetched once per client.
+func (c *pinnedKeysClient) GetKeysJson(ctx context.Context) (signatures.Keys, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys != nil {
+		return c.keys, nil
+	}
+
+	keys, err := c.Client.GetKeysJson(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, err := signatures.ReadPinned(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinned != nil {
+		changes, err := signatures.Compare(pinned, keys)
+		if err != nil {
+			return nil, fmt.Errorf("%w; if the registry announced this, remove %s to trust the new keys", err, c.path)
+		}
+		if changes.Empty() {
+			c.keys = keys
+			return keys, nil
+		}
+		c.warnChanges(changes)
+	}
+
+	if err := signatures.WritePinned(c.path, keys); err != nil {
+		_, _ = fmt.Fprintf(c.warn, "warning: failed to pin the registry's signing keys: %v\n", err)
+	}
+
+	c.keys = keys
+	return keys, nil
+}
+
+func (c *pinnedKeysClient) warnChanges(changes signatures.KeyChanges) {
+	var b strings.Builder
+	b.WriteString("warning: the registry's signing keys changed since they were pinned:\n")
+	for _, id := range changes.Added {
+		b.WriteString("  + added " + id + "\n")
+	}
+	for _, id := range changes.Removed {
+		b.WriteString("  - removed " + id + "\n")
+	}
+	for _, id := range changes.Expiry {
+		b.WriteString("  ~ new expiry for " + id + "\n")
+	}
+	b.WriteString("If the registry didn't announce a key rotation, stop and check before installing.\n")
+
+	_, _ = io.WriteString(c.warn, output.Sanitize(b.String()))
+}
@@ -0,0 +1,149 @@
+// Package releaseage holds back package versions published more recently
+// than a project's minimum release age, so that a hijacked release has time
+// to be noticed and pulled before it gets installed.
+package releaseage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+)
+
+// Gate is a minimum release age and the packages exempt from it.
+type Gate struct {
+	minimum time.Duration
+	exclude []string
+	now     func() time.Time
+}
+
+// New returns the gate for a minimum age such as "72h" or "3d", or nil if
+// minimum is empty. Entries of exclude are package names or patterns such as
+// "acme-*".
+func New(minimum string, exclude []string) (*Gate, error) {
+	if minimum == "" {
+		return nil, nil
+	}
+
+	d, err := parseDuration(minimum)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("invalid config.minimum-release-age %q: expected a duration such as \"72h\" or \"3d\"", minimum)
+	}
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid config.minimum-release-age-exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return &Gate{minimum: d, exclude: exclude, now: time.Now}, nil
+}
+
+// parseDuration accepts Go durations, plus whole days such as "3d".
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Exempt reports whether the package name is excluded from the gate.
+func (g *Gate) Exempt(name string) bool {
+	for _, pattern := range g.exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns a *TooNewError if the version in m was published less than
+// the minimum release age ago, and an error if the registry didn't say when it
+// was published.
+func (g *Gate) Check(m *manifest.Package) error {
+	if g.Exempt(m.Name) {
+		return nil
+	}
+	if m.PublishedAt == "" {
+		return errors.New("the registry didn't report when it was published, so its minimum release age can't be checked")
+	}
+	published, err := time.Parse(time.RFC3339, m.PublishedAt)
+	if err != nil {
+		return fmt.Errorf("invalid publish time %q: %w", m.PublishedAt, err)
+	}
+
+	now := g.now()
+	if eligible := published.Add(g.minimum); now.Before(eligible) {
+		return &TooNewError{Age: now.Sub(published), Minimum: g.minimum, Eligible: eligible, now: now}
+	}
+	return nil
+}
+
+// Newest returns the newest version in p, no newer than target, that the gate
+// lets through, or nil if there is none. Pre-releases are only considered when
+// target is one.
+func (g *Gate) Newest(p *manifest.Packument, target string) *manifest.Package {
+	limit, err := semver.NewVersion(target)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		newest    *manifest.Package
+		newestVer *semver.Version
+	)
+	for v, pkg := range p.Versions {
+		sv, err := semver.StrictNewVersion(v)
+		if err != nil || pkg == nil || sv.GreaterThan(limit) {
+			continue
+		}
+		if sv.Prerelease() != "" && limit.Prerelease() == "" {
+			continue
+		}
+		if newestVer != nil && !sv.GreaterThan(newestVer) {
+			continue
+		}
+		if g.Check(pkg) == nil {
+			newest, newestVer = pkg, sv
+		}
+	}
+	return newest
+}
+
+// TooNewError is a version published less than the minimum release age ago.
+type TooNewError struct {
+	Age      time.Duration // since it was published
+	Minimum  time.Duration
+	Eligible time.Time // when it becomes old enough
+	now      time.Time
+}
+
+func (e *TooNewError) Error() string {
+	return fmt.Sprintf("published %s ago, less than the minimum release age of %s; it becomes eligible on %s (in %s)",
+		humanDuration(e.Age), humanDuration(e.Minimum), e.Eligible.UTC().Format("2006-01-02 15:04 MST"), humanDuration(e.Eligible.Sub(e.now)))
+}
+
+// humanDuration rounds d to whole hours, as days and hours past two days, or
+// to whole minutes below an hour.
+func humanDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		hours := int(d.Round(time.Hour).Hours())
+		if hours%24 == 0 {
+			return strconv.Itoa(hours/24) + "d"
+		}
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	case d >= time.Hour:
+		return strconv.Itoa(int(d.Round(time.Hour).Hours())) + "h"
+	default:
+		return strconv.Itoa(max(int(d.Round(time.Minute).Minutes()), 1)) + "m"
+	}
+}
@@ -0,0 +1,114 @@
+package releaseage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func newGate(t *testing.T, minimum string, exclude ...string) *Gate {
+	t.Helper()
+	g, err := New(minimum, exclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.now = func() time.Time { return now }
+	return g
+}
+
+func published(name, version string, ago time.Duration) *manifest.Package {
+	return &manifest.Package{Name: name, Version: version, PublishedAt: now.Add(-ago).Format(time.RFC3339)}
+}
+
+func TestNew(t *testing.T) {
+	if g, err := New("", nil); g != nil || err != nil {
+		t.Errorf("New(\"\") = %v, %v; want nil, nil", g, err)
+	}
+	for _, minimum := range []string{"72h", "3d", "90m"} {
+		if _, err := New(minimum, nil); err != nil {
+			t.Errorf("New(%q) = %v", minimum, err)
+		}
+	}
+	for _, minimum := range []string{"soon", "-1h", "0d", "1.5d"} {
+		if _, err := New(minimum, nil); err == nil {
+			t.Errorf("New(%q) succeeded, want error", minimum)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	g := newGate(t, "72h", "acme-*")
+
+	if err := g.Check(published("akismet", "5.3.0", 100*time.Hour)); err != nil {
+		t.Errorf("old enough: %v", err)
+	}
+	if err := g.Check(published("acme-tools", "1.0.0", time.Hour)); err != nil {
+		t.Errorf("exempt: %v", err)
+	}
+	if err := g.Check(&manifest.Package{Name: "akismet", Version: "5.4.0"}); err == nil {
+		t.Error("no publish time: got nil error")
+	}
+
+	err := g.Check(published("akismet", "5.4.0", 20*time.Hour))
+	tooNew, ok := errors.AsType[*TooNewError](err)
+	if !ok {
+		t.Fatalf("too new: got %v, want *TooNewError", err)
+	}
+	if want := now.Add(52 * time.Hour); !tooNew.Eligible.Equal(want) {
+		t.Errorf("Eligible = %v, want %v", tooNew.Eligible, want)
+	}
+	want := "published 20h ago, less than the minimum release age of 3d; it becomes eligible on 2026-10-20 16:00 UTC (in 2d 4h)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
+
+func TestNewest(t *testing.T) {
+	g := newGate(t, "3d")
+	packument := &manifest.Packument{Versions: map[string]*manifest.Package{
+		"5.2.0":       published("akismet", "5.2.0", 30*24*time.Hour),
+		"5.3.0":       published("akismet", "5.3.0", 10*24*time.Hour),
+		"5.4.0-beta1": published("akismet", "5.4.0-beta1", 5*24*time.Hour),
+		"5.4.0":       published("akismet", "5.4.0", time.Hour),
+		"6.0.0":       published("akismet", "6.0.0", 20*24*time.Hour),
+	}}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"5.4.0", "5.3.0"},
+		{"5.4.0-rc1", "5.4.0-beta1"},
+		{"5.2.0", "5.2.0"},
+		{"5.1.0", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if m := g.Newest(packument, tt.target); m != nil {
+			got = m.Version
+		}
+		if got != tt.want {
+			t.Errorf("Newest(%s) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Second:               "1m",
+		42 * time.Minute:               "42m",
+		5*time.Hour + 20*time.Minute:   "5h",
+		47 * time.Hour:                 "47h",
+		72 * time.Hour:                 "3d",
+		100*time.Hour + 40*time.Minute: "4d 5h",
+	}
+	for d, want := range tests {
+		if got := humanDuration(d); got != want {
+			t.Errorf("humanDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"golang.org/x/sync/errgroup"
 
+	"go.wpm.so/cli/pkg/output"
+	"go.wpm.so/cli/pkg/pm/license"
+	"go.wpm.so/cli/pkg/pm/policy"
 	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/releaseage"
 	"go.wpm.so/cli/pkg/pm/signatures"
 	"go.wpm.so/cli/pkg/pm/wpmjson"
 	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
@@ -19,13 +23,15 @@ import (
 )
 
 type Node struct {
-	Name         string
-	Version      string
-	Type         types.PackageType
-	Signatures   []manifest.Signature
-	Digest       string              // Sha256 digest of the tarball
-	Bin          *types.Bin          `json:"bin,omitempty"`
-	Dependencies *types.Dependencies `json:"dependencies,omitempty"`
+	Name            string
+	Version         string
+	Type            types.PackageType
+	License         string
+	Signatures      []manifest.Signature
+	AuthorSignature *manifest.Signature
+	Digest          string              // Sha256 digest of the tarball
+	Bin             *types.Bin          `json:"bin,omitempty"`
+	Dependencies    *types.Dependencies `json:"dependencies,omitempty"`
 }
 
 type dependencyRequest struct {
@@ -39,6 +45,10 @@ type Resolver struct {
 	lockfile   *wpmlock.Lockfile
 	client     registry.Client
 	verifier   *signatures.Verifier
+	authors    *signatures.AuthorVerifier // nil unless wpm.json trusts author keys
+	licenses   *license.Policy            // nil unless wpm.json sets a license policy
+	policy     *policy.Policy             // nil unless wpm.json sets an install policy
+	releaseAge *releaseage.Gate           // nil unless wpm.json sets a minimum release age
 }
 
 func New(rootConfig *wpmjson.Config, lockfile *wpmlock.Lockfile, client registry.Client) *Resolver {
@@ -67,6 +77,24 @@ func (r *Resolver) Resolve(ctx context.Context, progress ProgressReporter, w io.
 	}
 	r.verifier = signatures.New(keys)
 
+	if authors := r.rootConfig.TrustedAuthors(); len(authors) > 0 {
+		if r.authors, err = signatures.NewAuthorVerifier(authors); err != nil {
+			return nil, err
+		}
+	}
+
+	if licenses := r.rootConfig.LicensePolicy(); licenses != nil {
+		r.licenses = &license.Policy{Allow: licenses.Allow, Deny: licenses.Deny}
+	}
+
+	if r.policy, err = policy.New(r.rootConfig.InstallPolicy()); err != nil {
+		return nil, err
+	}
+	if r.releaseAge, err = releaseage.New(r.rootConfig.MinimumReleaseAge()); err != nil {
+		return nil, err
+	}
+	var violations []policy.Violation
+
 	resolved := make(map[string]Node)
 	queue := r.seedQueue()
 
@@ -90,6 +118,9 @@ func (r *Resolver) Resolve(ctx context.Context, progress ProgressReporter, w io.
 		}
 
 		for _, res := range results {
+			if r.policy != nil {
+				violations = append(violations, r.policy.CheckPackage(res.manifest, r.signedBy(res.manifest))...)
+			}
 			children, err := r.applyResult(res, resolved)
 			if err != nil {
 				return nil, err
@@ -98,9 +129,31 @@ func (r *Resolver) Resolve(ctx context.Context, progress ProgressReporter, w io.
 		}
 	}
 
+	if len(violations) > 0 {
+		return nil, &policy.Error{Violations: violations}
+	}
 	return resolved, nil
 }
 
+// locked reports whether the lockfile has the version of package name.
+func (r *Resolver) locked(name, version string) bool {
+	if r.lockfile == nil {
+		return false
+	}
+	pkg, ok := r.lockfile.Packages[name]
+	return ok && pkg.Version == version
+}
+
+// signedBy returns the IDs of the registry and author keys that validly
+// signed a manifest that passed verification.
+func (r *Resolver) signedBy(m *manifest.Package) []string {
+	ids := r.verifier.SignedBy(m)
+	if r.authors != nil && m.Dist.AuthorSignature != nil {
+		ids = append(ids, m.Dist.AuthorSignature.KeyID)
+	}
+	return ids
+}
+
 func (r *Resolver) seedQueue() []dependencyRequest {
 	n := 0
 	if r.rootConfig.Dependencies != nil {
@@ -161,6 +214,22 @@ func (r *Resolver) fetchAll(ctx context.Context, requests map[requestKey]depende
 			if err := r.verifier.Verify(manifest); err != nil {
 				return fmt.Errorf("signature verification failed for %s@%s required by %s: %w", req.name, req.version, req.requestor, err)
 			}
+			if r.authors != nil {
+				if err := r.authors.Verify(manifest); err != nil {
+					return fmt.Errorf("author signature verification failed for %s@%s required by %s: %w", req.name, req.version, req.requestor, err)
+				}
+			}
+			// Locked versions were accepted when they were first resolved.
+			if r.releaseAge != nil && !r.locked(req.name, req.version) {
+				if err := r.releaseAge.Check(manifest); err != nil {
+					return fmt.Errorf("refusing %s@%s required by %s: %w", req.name, req.version, req.requestor, err)
+				}
+			}
+			if r.licenses != nil {
+				if err := r.licenses.Check(manifest.License); err != nil {
+					return fmt.Errorf("refusing %s@%s required by %s: %s", req.name, req.version, req.requestor, output.Sanitize(err.Error()))
+				}
+			}
 			results <- fetchResult{req: req, manifest: manifest}
 			return nil
 		})
@@ -200,13 +269,15 @@ func (r *Resolver) applyResult(res fetchResult, resolved map[string]Node) ([]dep
 	}
 
 	resolved[res.req.name] = Node{
-		Name:         res.manifest.Name,
-		Version:      res.manifest.Version,
-		Type:         res.manifest.Type,
-		Signatures:   res.manifest.Dist.Signatures,
-		Digest:       res.manifest.Dist.Digest,
-		Bin:          res.manifest.Bin,
-		Dependencies: res.manifest.Dependencies,
+		Name:            res.manifest.Name,
+		Version:         res.manifest.Version,
+		Type:            res.manifest.Type,
+		License:         res.manifest.License,
+		Signatures:      res.manifest.Dist.Signatures,
+		AuthorSignature: res.manifest.Dist.AuthorSignature,
+		Digest:          res.manifest.Dist.Digest,
+		Bin:             res.manifest.Bin,
+		Dependencies:    res.manifest.Dependencies,
 	}
 
 	if res.manifest.Dependencies == nil {
@@ -225,6 +296,8 @@ type ResolutionError struct {
 	Action string
 }
 
+// Error describes the conflict. Names and versions in it come from registry
+// manifests, so the message is sanitized.
 func (e *ResolutionError) Error() string {
 	msg := e.Header + "\n"
 	var builder strings.Builder
@@ -235,7 +308,7 @@ func (e *ResolutionError) Error() string {
 	}
 	msg += builder.String()
 	msg += "Action: " + e.Action
-	return msg
+	return output.Sanitize(msg)
 }
 
 func (r *Resolver) resolveConflict(req dependencyRequest, existing Node) error {
@@ -308,26 +381,23 @@ func (r *Resolver) checkRuntimeCompatibility(pkg *manifest.Package) error {
 		return nil
 	}
 
-	// If manifest has no requirements, skip
-	if pkg.Requires == nil {
+	return CheckRuntime(pkg.Requires, r.rootConfig.Config.Runtime)
+}
+
+// CheckRuntime reports why a package with the given requirements cannot run
+// on runtime, or nil if it can. Either may be nil.
+func CheckRuntime(requires *types.Requires, runtime *types.Runtime) error {
+	if requires == nil || runtime == nil {
 		return nil
 	}
 
-	// PHP and WordPress version constraints
-	requiresWP := pkg.Requires.WP
-	requiresPHP := pkg.Requires.PHP
-
-	// PHP and WordPress runtime versions
-	runtimeWP := r.rootConfig.Config.Runtime.WP
-	runtimePHP := r.rootConfig.Config.Runtime.PHP
-
 	// Check WordPress runtime compatibility.
-	if err := checkVersionCompatibility("WordPress", requiresWP, runtimeWP); err != nil {
+	if err := checkVersionCompatibility("WordPress", requires.WP, runtime.WP); err != nil {
 		return err
 	}
 
 	// Check PHP runtime compatibility.
-	if err := checkVersionCompatibility("PHP", requiresPHP, runtimePHP); err != nil {
+	if err := checkVersionCompatibility("PHP", requires.PHP, runtime.PHP); err != nil {
 		return err
 	}
 
@@ -360,16 +430,20 @@ func (r *Resolver) fetchMetadata(ctx context.Context, name, version string) (*ma
 	// Try to resolve the manifest from lockfile first
 	if r.lockfile != nil && r.lockfile.Packages != nil {
 		if lockPkg, ok := r.lockfile.Packages[name]; ok {
-			if lockPkg.Version == version {
+			// Lockfiles written before licenses were recorded are missing
+			// them, so a license policy needs the registry's manifest.
+			if lockPkg.Version == version && (r.licenses == nil || lockPkg.License != "") {
 				return &manifest.Package{
 					Name:         name,
 					Version:      lockPkg.Version,
 					Type:         lockPkg.Type,
+					License:      lockPkg.License,
 					Bin:          lockPkg.Bin,
 					Dependencies: lockPkg.Dependencies,
 					Dist: manifest.Dist{
-						Digest:     lockPkg.Digest,
-						Signatures: lockPkg.Signatures,
+						Digest:          lockPkg.Digest,
+						Signatures:      lockPkg.Signatures,
+						AuthorSignature: lockPkg.AuthorSignature,
 					},
 				}, nil
 			}
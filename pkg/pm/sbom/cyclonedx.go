@@ -0,0 +1,167 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	Scope      string        `json:"scope,omitempty"`
+	Hashes     []cdxHash     `json:"hashes,omitempty"`
+	Licenses   []cdxLicense  `json:"licenses,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	CPE        string        `json:"cpe,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxLicense struct {
+	Expression string           `json:"expression,omitempty"`
+	License    *cdxNamedLicense `json:"license,omitempty"`
+}
+
+type cdxNamedLicense struct {
+	Name string `json:"name"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// WriteCycloneDX writes d to w as a CycloneDX 1.5 JSON document.
+func WriteCycloneDX(w io.Writer, d *Document) error {
+	const rootRef = "root"
+
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: d.Created.Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: "wpm", Version: d.Tool},
+			}},
+			Component: cdxComponent{
+				Type:     "application",
+				BOMRef:   rootRef,
+				Name:     d.Name,
+				Version:  d.Version,
+				Licenses: cdxLicenses(d.License),
+			},
+		},
+		Components:   make([]cdxComponent, 0, len(d.Components)),
+		Dependencies: make([]cdxDependency, 0, len(d.Components)+1),
+	}
+
+	bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: rootRef, DependsOn: orEmpty(d.DependsOn)})
+	for _, c := range d.Components {
+		bom.Components = append(bom.Components, cdxComponentFor(c))
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: c.Ref, DependsOn: orEmpty(c.DependsOn)})
+	}
+
+	return writeJSON(w, bom)
+}
+
+func cdxComponentFor(c Component) cdxComponent {
+	cc := cdxComponent{
+		BOMRef:   c.Ref,
+		Name:     c.Name,
+		Version:  c.Version,
+		Licenses: cdxLicenses(c.License),
+	}
+
+	switch c.Kind {
+	case KindWordPress:
+		cc.Type = "framework"
+		cc.CPE = cpe("wordpress", "wordpress", c.Version)
+	case KindPHP:
+		cc.Type = "platform"
+		cc.CPE = cpe("php", "php", c.Version)
+	default:
+		cc.Type = "library"
+		cc.PURL = purl(c)
+		cc.Properties = []cdxProperty{{Name: "wpm:type", Value: c.Kind}}
+		if c.Dev {
+			// CycloneDX documents non-runtime components as excluded.
+			cc.Scope = "excluded"
+		} else {
+			cc.Scope = "required"
+		}
+	}
+
+	if c.SHA256 != "" {
+		cc.Hashes = []cdxHash{{Alg: "SHA-256", Content: c.SHA256}}
+	}
+	return cc
+}
+
+func cdxLicenses(license string) []cdxLicense {
+	switch {
+	case license == "":
+		return nil
+	case isSPDXExpression(license):
+		return []cdxLicense{{Expression: license}}
+	default:
+		return []cdxLicense{{License: &cdxNamedLicense{Name: license}}}
+	}
+}
+
+// purl returns the package URL of a wpm package. There is no registered
+// purl type for wpm, so the generic type is used.
+func purl(c Component) string {
+	return "pkg:generic/" + c.Name + "@" + c.Version
+}
+
+func cpe(vendor, product, version string) string {
+	return "cpe:2.3:a:" + vendor + ":" + product + ":" + version + ":*:*:*:*:*:*:*"
+}
+
+func orEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,220 @@
+// Package sbom builds a software bill of materials for a project from its
+// wpm.json and wpm.lock, and encodes it as CycloneDX or SPDX JSON.
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+const (
+	// KindWordPress and KindPHP are the kinds of the runtime components.
+	KindWordPress = "wordpress"
+	KindPHP       = "php"
+)
+
+var spdxLicenseID = regexp.MustCompile(`^[A-Za-z0-9.-]+\+?$`)
+
+// Component is a package, or a runtime, the project is made of.
+type Component struct {
+	Ref       string // unique within the document
+	Name      string
+	Version   string
+	Kind      string // plugin, theme, wordpress or php
+	License   string
+	SHA256    string // hex, empty if unknown
+	Dev       bool   // only needed by devDependencies
+	DependsOn []string
+}
+
+// Document is a bill of materials, independent of its encoding.
+type Document struct {
+	Tool       string // version of wpm that generated the document
+	Name       string
+	Version    string
+	License    string
+	Created    time.Time
+	Components []Component
+	DependsOn  []string // refs of the project's direct dependencies
+}
+
+// Options tunes what goes into a Document.
+type Options struct {
+	NoDev   bool // leave out packages only needed by devDependencies
+	Runtime bool // add WordPress and PHP from config.runtime
+}
+
+// New builds the document for the project named name. Lockfiles written
+// before licenses were recorded leave packages without one, for the caller
+// to fill in from their manifests.
+func New(name string, config *wpmjson.Config, lock *wpmlock.Lockfile, opts Options) *Document {
+	doc := &Document{
+		Name:    name,
+		Version: config.Version,
+		License: config.License,
+		Created: time.Now().UTC(),
+	}
+
+	prod := reachable(lock, depNames(config.Dependencies))
+
+	for _, pkgName := range slices.Sorted(maps.Keys(lock.Packages)) {
+		pkg := lock.Packages[pkgName]
+		dev := !prod[pkgName]
+		if dev && opts.NoDev {
+			continue
+		}
+
+		c := Component{
+			Ref:     packageRef(pkgName, pkg.Version),
+			Name:    pkgName,
+			Version: pkg.Version,
+			Kind:    string(pkg.Type),
+			License: pkg.License,
+			SHA256:  sha256Hex(pkg.Digest),
+			Dev:     dev,
+		}
+		if pkg.Dependencies != nil {
+			for _, dep := range slices.Sorted(maps.Keys(*pkg.Dependencies)) {
+				if ref, ok := lockedRef(lock, dep); ok {
+					c.DependsOn = append(c.DependsOn, ref)
+				}
+			}
+		}
+		doc.Components = append(doc.Components, c)
+	}
+
+	roots := depNames(config.Dependencies)
+	if !opts.NoDev {
+		roots = append(roots, depNames(config.DevDependencies)...)
+	}
+	slices.Sort(roots)
+	for _, dep := range slices.Compact(roots) {
+		if ref, ok := lockedRef(lock, dep); ok {
+			doc.DependsOn = append(doc.DependsOn, ref)
+		}
+	}
+
+	if opts.Runtime && config.Config != nil && config.Config.Runtime != nil {
+		doc.addRuntime(config.Config.Runtime)
+	}
+
+	return doc
+}
+
+func (d *Document) addRuntime(runtime *types.Runtime) {
+	for _, r := range []struct{ kind, version string }{
+		{KindWordPress, runtime.WP},
+		{KindPHP, runtime.PHP},
+	} {
+		if r.version == "" {
+			continue
+		}
+		ref := "runtime:" + r.kind + "@" + r.version
+		d.Components = append(d.Components, Component{
+			Ref:     ref,
+			Name:    r.kind,
+			Version: r.version,
+			Kind:    r.kind,
+		})
+		d.DependsOn = append(d.DependsOn, ref)
+	}
+}
+
+func packageRef(name, version string) string {
+	return name + "@" + version
+}
+
+// lockedRef returns the ref of the locked version of name. wpm installs a
+// single version of each package, so a dependency is whatever is locked.
+func lockedRef(lock *wpmlock.Lockfile, name string) (string, bool) {
+	pkg, ok := lock.Packages[name]
+	if !ok {
+		return "", false
+	}
+	return packageRef(name, pkg.Version), true
+}
+
+func depNames(deps *types.Dependencies) []string {
+	if deps == nil {
+		return nil
+	}
+	return slices.Collect(maps.Keys(*deps))
+}
+
+// reachable returns the locked packages that roots depend on, directly or not.
+func reachable(lock *wpmlock.Lockfile, roots []string) map[string]bool {
+	seen := make(map[string]bool)
+	queue := roots
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		pkg, ok := lock.Packages[name]
+		if !ok {
+			continue
+		}
+		seen[name] = true
+		if pkg.Dependencies != nil {
+			queue = append(queue, depNames(pkg.Dependencies)...)
+		}
+	}
+	return seen
+}
+
+// sha256Hex converts a "sha256:" digest, base64 or hex encoded, to hex. It
+// returns "" for anything else.
+func sha256Hex(digest string) string {
+	s, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok {
+		return ""
+	}
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil && len(b) == 32 {
+		return hex.EncodeToString(b)
+	}
+	if b, err := hex.DecodeString(s); err == nil && len(b) == 32 {
+		return strings.ToLower(s)
+	}
+	return ""
+}
+
+// isSPDXExpression reports whether license looks like an SPDX license
+// expression, e.g. "GPL-2.0-or-later" or "(MIT OR Apache-2.0)". Free text
+// such as "GPLv2 or later" doesn't.
+func isSPDXExpression(license string) bool {
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(license))
+	if len(fields)%2 == 0 {
+		return false
+	}
+	for i, f := range fields {
+		if i%2 == 1 {
+			if f != "AND" && f != "OR" && f != "WITH" {
+				return false
+			}
+		} else if !spdxLicenseID.MatchString(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,150 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+const digest = "sha256:n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg="
+
+func testProject() (*wpmjson.Config, *wpmlock.Lockfile) {
+	config := &wpmjson.Config{
+		Name:            "my-site",
+		Version:         "1.0.0",
+		Dependencies:    &types.Dependencies{"akismet": "5.4.0"},
+		DevDependencies: &types.Dependencies{"query-monitor": "3.16.0"},
+		Config:          &types.PackageConfig{Runtime: &types.Runtime{WP: "6.9", PHP: "8.2"}},
+	}
+	lock := &wpmlock.Lockfile{Packages: map[string]wpmlock.LockPackage{
+		"akismet":       {Version: "5.4.0", Type: types.TypePlugin, Digest: digest, Dependencies: &types.Dependencies{"helper": "1.0.0"}},
+		"helper":        {Version: "1.0.0", Type: types.TypePlugin},
+		"query-monitor": {Version: "3.16.0", Type: types.TypePlugin, Dependencies: &types.Dependencies{"debug-bar": "1.1.0"}},
+		"debug-bar":     {Version: "1.1.0", Type: types.TypePlugin},
+	}}
+	return config, lock
+}
+
+func TestNew(t *testing.T) {
+	config, lock := testProject()
+
+	doc := New("my-site", config, lock, Options{})
+	var refs, dev []string
+	for _, c := range doc.Components {
+		refs = append(refs, c.Ref)
+		if c.Dev {
+			dev = append(dev, c.Name)
+		}
+	}
+	if want := []string{"akismet@5.4.0", "debug-bar@1.1.0", "helper@1.0.0", "query-monitor@3.16.0"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("components = %v, want %v", refs, want)
+	}
+	if want := []string{"debug-bar", "query-monitor"}; !reflect.DeepEqual(dev, want) {
+		t.Errorf("dev components = %v, want %v", dev, want)
+	}
+	if want := []string{"akismet@5.4.0", "query-monitor@3.16.0"}; !reflect.DeepEqual(doc.DependsOn, want) {
+		t.Errorf("root depends on %v, want %v", doc.DependsOn, want)
+	}
+	if want := []string{"helper@1.0.0"}; !reflect.DeepEqual(doc.Components[0].DependsOn, want) {
+		t.Errorf("akismet depends on %v, want %v", doc.Components[0].DependsOn, want)
+	}
+	if got, want := doc.Components[0].SHA256, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"; got != want {
+		t.Errorf("akismet SHA256 = %q, want %q", got, want)
+	}
+
+	doc = New("my-site", config, lock, Options{NoDev: true, Runtime: true})
+	refs = nil
+	for _, c := range doc.Components {
+		refs = append(refs, c.Ref)
+	}
+	if want := []string{"akismet@5.4.0", "helper@1.0.0", "runtime:wordpress@6.9", "runtime:php@8.2"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("components with NoDev and Runtime = %v, want %v", refs, want)
+	}
+	if want := []string{"akismet@5.4.0", "runtime:wordpress@6.9", "runtime:php@8.2"}; !reflect.DeepEqual(doc.DependsOn, want) {
+		t.Errorf("root with NoDev and Runtime depends on %v, want %v", doc.DependsOn, want)
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	config, lock := testProject()
+	doc := New("my-site", config, lock, Options{Runtime: true})
+	doc.Components[0].License = "GPL-2.0-or-later"
+
+	var buf bytes.Buffer
+	if err := WriteCycloneDX(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatal(err)
+	}
+	akismet := bom.Components[0]
+	if akismet.Scope != "required" || akismet.PURL != "pkg:generic/akismet@5.4.0" || len(akismet.Hashes) != 1 {
+		t.Errorf("akismet component = %+v", akismet)
+	}
+	if akismet.Licenses[0].Expression != "GPL-2.0-or-later" {
+		t.Errorf("akismet licenses = %+v, want an SPDX expression", akismet.Licenses)
+	}
+	if qm := bom.Components[3]; qm.Scope != "excluded" {
+		t.Errorf("dev component scope = %q, want excluded", qm.Scope)
+	}
+	if wp := bom.Components[4]; wp.Type != "framework" || wp.CPE != "cpe:2.3:a:wordpress:wordpress:6.9:*:*:*:*:*:*:*" {
+		t.Errorf("WordPress component = %+v", wp)
+	}
+	if len(bom.Dependencies) != len(bom.Components)+1 || bom.Dependencies[0].Ref != "root" {
+		t.Errorf("dependencies = %+v, want one per component plus the root", bom.Dependencies)
+	}
+}
+
+func TestWriteSPDX(t *testing.T) {
+	config, lock := testProject()
+	doc := New("my-site", config, lock, Options{})
+	doc.License = "GPLv2 or later"
+
+	var buf bytes.Buffer
+	if err := WriteSPDX(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	var spdx spdxDocument
+	if err := json.Unmarshal(buf.Bytes(), &spdx); err != nil {
+		t.Fatal(err)
+	}
+	if spdx.Packages[0].LicenseDeclared != "LicenseRef-1" || len(spdx.ExtractedLicenses) != 1 {
+		t.Errorf("free text license declared as %q with %+v", spdx.Packages[0].LicenseDeclared, spdx.ExtractedLicenses)
+	}
+
+	want := []spdxRelationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Project"},
+		{"SPDXRef-Package-akismet", "DEPENDS_ON", "SPDXRef-Package-helper"},
+		{"SPDXRef-Package-query-monitor", "DEPENDS_ON", "SPDXRef-Package-debug-bar"},
+		{"SPDXRef-Project", "DEPENDS_ON", "SPDXRef-Package-akismet"},
+		{"SPDXRef-Package-query-monitor", "DEV_DEPENDENCY_OF", "SPDXRef-Project"},
+	}
+	if !reflect.DeepEqual(spdx.Relationships, want) {
+		t.Errorf("relationships = %+v, want %+v", spdx.Relationships, want)
+	}
+}
+
+func TestIsSPDXExpression(t *testing.T) {
+	tests := map[string]bool{
+		"GPL-2.0-or-later":                     true,
+		"GPL-2.0+":                             true,
+		"(MIT OR Apache-2.0)":                  true,
+		"GPL-2.0 WITH Classpath-exception-2.0": true,
+		"GPLv2 or later":                       false,
+		"MIT OR":                               false,
+		"Proprietary, see LICENSE":             false,
+	}
+	for license, want := range tests {
+		if got := isSPDXExpression(license); got != want {
+			t.Errorf("isSPDXExpression(%q) = %v, want %v", license, got, want)
+		}
+	}
+}
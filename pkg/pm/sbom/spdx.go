@@ -0,0 +1,173 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type spdxDocument struct {
+	SPDXVersion       string                 `json:"spdxVersion"`
+	DataLicense       string                 `json:"dataLicense"`
+	SPDXID            string                 `json:"SPDXID"`
+	Name              string                 `json:"name"`
+	DocumentNamespace string                 `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo       `json:"creationInfo"`
+	Packages          []spdxPackage          `json:"packages"`
+	Relationships     []spdxRelationship     `json:"relationships"`
+	ExtractedLicenses []spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	Name          string `json:"name"`
+	ExtractedText string `json:"extractedText"`
+}
+
+const noAssertion = "NOASSERTION"
+
+// WriteSPDX writes d to w as an SPDX 2.3 JSON document.
+func WriteSPDX(w io.Writer, d *Document) error {
+	const (
+		docID  = "SPDXRef-DOCUMENT"
+		rootID = "SPDXRef-Project"
+	)
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            docID,
+		Name:              d.Name,
+		DocumentNamespace: "https://wpm.so/spdx/" + d.Name + "-" + newUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  d.Created.Format(time.RFC3339),
+			Creators: []string{"Tool: wpm-" + d.Tool},
+		},
+		Packages:      make([]spdxPackage, 0, len(d.Components)+1),
+		Relationships: []spdxRelationship{{docID, "DESCRIBES", rootID}},
+	}
+
+	// Licenses that aren't SPDX expressions are declared as LicenseRefs.
+	licenseRefs := make(map[string]string)
+	declare := func(license string) string {
+		switch {
+		case license == "":
+			return noAssertion
+		case isSPDXExpression(license):
+			return license
+		}
+		if id, ok := licenseRefs[license]; ok {
+			return id
+		}
+		id := fmt.Sprintf("LicenseRef-%d", len(licenseRefs)+1)
+		licenseRefs[license] = id
+		doc.ExtractedLicenses = append(doc.ExtractedLicenses, spdxExtractedLicense{LicenseID: id, Name: license, ExtractedText: license})
+		return id
+	}
+
+	doc.Packages = append(doc.Packages, spdxPackage{
+		Name:                  d.Name,
+		SPDXID:                rootID,
+		VersionInfo:           d.Version,
+		DownloadLocation:      noAssertion,
+		LicenseConcluded:      noAssertion,
+		LicenseDeclared:       declare(d.License),
+		PrimaryPackagePurpose: "APPLICATION",
+	})
+
+	ids := make(map[string]string, len(d.Components))
+	for _, c := range d.Components {
+		ids[c.Ref] = spdxID(c)
+	}
+
+	for _, c := range d.Components {
+		p := spdxPackage{
+			Name:             c.Name,
+			SPDXID:           ids[c.Ref],
+			VersionInfo:      c.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  declare(c.License),
+		}
+		switch c.Kind {
+		case KindWordPress:
+			p.PrimaryPackagePurpose = "FRAMEWORK"
+			p.ExternalRefs = []spdxExternalRef{{"SECURITY", "cpe23Type", cpe("wordpress", "wordpress", c.Version)}}
+		case KindPHP:
+			p.PrimaryPackagePurpose = "OPERATING-SYSTEM"
+			p.ExternalRefs = []spdxExternalRef{{"SECURITY", "cpe23Type", cpe("php", "php", c.Version)}}
+		default:
+			p.PrimaryPackagePurpose = "LIBRARY"
+			p.ExternalRefs = []spdxExternalRef{{"PACKAGE-MANAGER", "purl", purl(c)}}
+		}
+		if c.SHA256 != "" {
+			p.Checksums = []spdxChecksum{{"SHA256", c.SHA256}}
+		}
+		doc.Packages = append(doc.Packages, p)
+
+		for _, dep := range c.DependsOn {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{ids[c.Ref], "DEPENDS_ON", ids[dep]})
+		}
+	}
+
+	devOnly := make(map[string]bool, len(d.Components))
+	for _, c := range d.Components {
+		devOnly[c.Ref] = c.Dev
+	}
+	for _, dep := range d.DependsOn {
+		if devOnly[dep] {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{ids[dep], "DEV_DEPENDENCY_OF", rootID})
+		} else {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{rootID, "DEPENDS_ON", ids[dep]})
+		}
+	}
+
+	return writeJSON(w, doc)
+}
+
+// spdxID returns the SPDX identifier of c. Package names only use letters,
+// digits and hyphens, which are all valid in one.
+func spdxID(c Component) string {
+	switch c.Kind {
+	case KindWordPress, KindPHP:
+		return "SPDXRef-Runtime-" + c.Kind
+	default:
+		return "SPDXRef-Package-" + strings.ReplaceAll(c.Name, "_", "-")
+	}
+}
@@ -0,0 +1,184 @@
+package signatures
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+// Signer signs a payload on behalf of a package author.
+type Signer func(ctx context.Context, msg []byte) (manifest.Signature, error)
+
+// SignManifest signs the manifest's payload, the same one the registry signs,
+// and stores the result as its author signature.
+func SignManifest(ctx context.Context, m *manifest.Package, sign Signer) error {
+	msg, err := manifestPayload(m)
+	if err != nil {
+		return err
+	}
+	sig, err := sign(ctx, msg)
+	if err != nil {
+		return err
+	}
+	m.Dist.AuthorSignature = &sig
+	return nil
+}
+
+// ReadSigningKey reads an ECDSA P-256 private key from a PEM file, in either
+// SEC 1 ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY") form.
+func ReadSigningKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM encoded key", path)
+	}
+
+	var priv any
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		priv, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s holds a %q block, want an EC private key", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	ec, ok := priv.(*ecdsa.PrivateKey)
+	if !ok || ec.Curve != elliptic.P256() {
+		return nil, errors.New("signing key must be an ECDSA P-256 key")
+	}
+	return ec, nil
+}
+
+// KeySigner returns a Signer that signs with priv.
+func KeySigner(priv *ecdsa.PrivateKey) (Signer, error) {
+	keyID, _, err := KeyID(&priv.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return func(_ context.Context, msg []byte) (manifest.Signature, error) {
+		hash := sha256.Sum256(msg)
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+		if err != nil {
+			return manifest.Signature{}, err
+		}
+		return manifest.Signature{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}, nil
+	}, nil
+}
+
+// CommandSigner returns a Signer that runs an external command, such as a
+// wrapper around a hardware key or a cloud KMS. The command line is split on
+// whitespace, without a shell. The payload is written to its stdin, and it
+// must print the signature as JSON: {"keyid": "SHA256:...", "sig": "..."}.
+func CommandSigner(command string) (Signer, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("signer command is empty")
+	}
+	return func(ctx context.Context, msg []byte) (manifest.Signature, error) {
+		var stdout, stderr bytes.Buffer
+		//nolint:gosec // The command is the user's own signer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdin = bytes.NewReader(msg)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if detail := strings.TrimSpace(stderr.String()); detail != "" {
+				return manifest.Signature{}, fmt.Errorf("signer %s failed: %w: %s", args[0], err, detail)
+			}
+			return manifest.Signature{}, fmt.Errorf("signer %s failed: %w", args[0], err)
+		}
+
+		var sig manifest.Signature
+		if err := json.Unmarshal(stdout.Bytes(), &sig); err != nil {
+			return manifest.Signature{}, fmt.Errorf("signer %s printed invalid JSON: %w", args[0], err)
+		}
+		if sig.KeyID == "" || sig.Sig == "" {
+			return manifest.Signature{}, fmt.Errorf("signer %s must print both keyid and sig", args[0])
+		}
+		return manifest.Signature{KeyID: sig.KeyID, Sig: sig.Sig}, nil
+	}, nil
+}
+
+// KeyID returns the fingerprint of pub, "SHA256:" followed by the unpadded
+// base64 SHA-256 of its DER encoding, along with that base64 DER encoding.
+func KeyID(pub *ecdsa.PublicKey) (keyID, pubKey string, err error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), base64.StdEncoding.EncodeToString(der), nil
+}
+
+// AuthorVerifier checks author signatures against the keys a project trusts.
+type AuthorVerifier struct {
+	keys map[string]*ecdsa.PublicKey
+}
+
+// NewAuthorVerifier returns an AuthorVerifier backed by keys. Unlike the
+// registry's keys, these come from wpm.json, so a key that can't be parsed
+// or doesn't match its key ID is an error rather than skipped.
+func NewAuthorVerifier(keys []types.AuthorKey) (*AuthorVerifier, error) {
+	parsed := make(map[string]*ecdsa.PublicKey, len(keys))
+	for _, k := range keys {
+		pub, err := parseECDSAKey(k.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted author key %s: %w", k.KeyID, err)
+		}
+		keyID, _, err := KeyID(pub)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted author key %s: %w", k.KeyID, err)
+		}
+		if keyID != k.KeyID {
+			return nil, fmt.Errorf("trusted author key %s has the fingerprint %s", k.KeyID, keyID)
+		}
+		parsed[k.KeyID] = pub
+	}
+	return &AuthorVerifier{keys: parsed}, nil
+}
+
+// Verify checks that the manifest carries a valid author signature made with
+// one of the trusted keys.
+func (v *AuthorVerifier) Verify(m *manifest.Package) error {
+	sig := m.Dist.AuthorSignature
+	if sig == nil {
+		return errors.New("no author signature found")
+	}
+
+	pub, ok := v.keys[sig.KeyID]
+	if !ok {
+		return fmt.Errorf("author key %s is not trusted in wpm.json", sig.KeyID)
+	}
+
+	msg, err := manifestPayload(m)
+	if err != nil {
+		return err
+	}
+	if err := verifyECDSA(pub, sig.Sig, msg); err != nil {
+		return fmt.Errorf("author KeyID %s: %w", sig.KeyID, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package signatures
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func TestAuthorSignature(t *testing.T) {
+	author := newAuthorKey(t)
+	other := newAuthorKey(t)
+
+	signer, err := KeySigner(author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := &manifest.Package{
+		Name:         "akismet",
+		Version:      "5.4.0",
+		Dependencies: &types.Dependencies{"hello-dolly": "1.7.2"},
+		Dist:         manifest.Dist{Digest: "sha256:abc"},
+	}
+	if err := SignManifest(context.Background(), pkg, signer); err != nil {
+		t.Fatal(err)
+	}
+
+	trusted, err := NewAuthorVerifier([]types.AuthorKey{trustedAuthor(t, author)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trusted.Verify(pkg); err != nil {
+		t.Errorf("Verify() = %v, want nil", err)
+	}
+
+	untrusted, err := NewAuthorVerifier([]types.AuthorKey{trustedAuthor(t, other)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := untrusted.Verify(pkg); err == nil || !strings.Contains(err.Error(), "is not trusted") {
+		t.Errorf("Verify(untrusted) = %v, want a not trusted error", err)
+	}
+
+	tampered := *pkg
+	tampered.Dist.Digest = "sha256:def"
+	if err := trusted.Verify(&tampered); err == nil {
+		t.Error("Verify(tampered digest) = nil, want an error")
+	}
+
+	unsigned := *pkg
+	unsigned.Dist.AuthorSignature = nil
+	if err := trusted.Verify(&unsigned); err == nil || !strings.Contains(err.Error(), "no author signature") {
+		t.Errorf("Verify(unsigned) = %v, want a missing signature error", err)
+	}
+}
+
+func TestNewAuthorVerifierRejectsMismatchedKeyID(t *testing.T) {
+	k := trustedAuthor(t, newAuthorKey(t))
+	k.KeyID = "SHA256:someone-else"
+	if _, err := NewAuthorVerifier([]types.AuthorKey{k}); err == nil {
+		t.Fatal("NewAuthorVerifier() = nil error, want an error for a mismatched key ID")
+	}
+}
+
+func newAuthorKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func trustedAuthor(t *testing.T, priv *ecdsa.PrivateKey) types.AuthorKey {
+	t.Helper()
+	keyID, pubKey, err := KeyID(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return types.AuthorKey{KeyID: keyID, PubKey: pubKey}
+}
@@ -0,0 +1,90 @@
+package signatures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"go.wpm.so/cli/pkg/atomicwriter"
+)
+
+// KeyChanges lists, by key ID, how a key set differs from the pinned one.
+type KeyChanges struct {
+	Added   []string
+	Removed []string
+	Expiry  []string // keys whose expiry was set or changed
+}
+
+// Empty reports whether the key sets are the same.
+func (c KeyChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Expiry) == 0
+}
+
+// ReadPinned reads the key set pinned at path. It returns nil if nothing has
+// been pinned yet.
+func ReadPinned(path string) (Keys, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pinned signing keys: %w", err)
+	}
+
+	var keys Keys
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse pinned signing keys in %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// WritePinned pins keys at path.
+func WritePinned(path string, keys Keys) error {
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return atomicwriter.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Compare returns how keys differ from pinned. Keys may be added, removed or
+// given an expiry, which is how a registry rotates them, but a key ID must
+// never change its public key: that is an error, whatever else changed.
+func Compare(pinned, keys Keys) (KeyChanges, error) {
+	var changes KeyChanges
+
+	old := make(map[string]key, len(pinned))
+	for _, k := range pinned {
+		old[k.KeyID] = k
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		seen[k.KeyID] = true
+		prev, ok := old[k.KeyID]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, k.KeyID)
+		case prev.PubKey != k.PubKey || prev.Type != k.Type:
+			return KeyChanges{}, fmt.Errorf("the public key for KeyID %s changed since it was pinned", k.KeyID)
+		case prev.Expires != k.Expires:
+			changes.Expiry = append(changes.Expiry, k.KeyID)
+		}
+	}
+	for _, k := range pinned {
+		if !seen[k.KeyID] {
+			changes.Removed = append(changes.Removed, k.KeyID)
+		}
+	}
+
+	slices.Sort(changes.Added)
+	slices.Sort(changes.Removed)
+	slices.Sort(changes.Expiry)
+	return changes, nil
+}
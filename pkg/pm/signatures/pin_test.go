@@ -0,0 +1,50 @@
+package signatures
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	a := key{Type: signingAlgorithm, KeyID: "SHA256:a", PubKey: "AAA"}
+	b := key{Type: signingAlgorithm, KeyID: "SHA256:b", PubKey: "BBB"}
+	c := key{Type: signingAlgorithm, KeyID: "SHA256:c", PubKey: "CCC"}
+	aExpiring := a
+	aExpiring.Expires = "2026-01-01T00:00:00Z"
+	aSwapped := a
+	aSwapped.PubKey = "EVIL"
+
+	changes, err := Compare(Keys{a, b}, Keys{a, b})
+	if err != nil || !changes.Empty() {
+		t.Errorf("Compare(same) = %+v, %v, want no changes", changes, err)
+	}
+
+	changes, err = Compare(Keys{a, b}, Keys{aExpiring, c})
+	want := KeyChanges{Added: []string{"SHA256:c"}, Removed: []string{"SHA256:b"}, Expiry: []string{"SHA256:a"}}
+	if err != nil || !reflect.DeepEqual(changes, want) {
+		t.Errorf("Compare(rotated) = %+v, %v, want %+v", changes, err, want)
+	}
+
+	if _, err := Compare(Keys{a, b}, Keys{aSwapped, b}); err == nil {
+		t.Error("Compare(swapped public key) = nil error, want an error")
+	}
+}
+
+func TestPinnedRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "registry.wpm.so.json")
+
+	keys, err := ReadPinned(path)
+	if err != nil || keys != nil {
+		t.Fatalf("ReadPinned(missing) = %v, %v, want nil, nil", keys, err)
+	}
+
+	want := Keys{{Type: signingAlgorithm, KeyID: "SHA256:a", PubKey: "AAA", Expires: "2026-01-01T00:00:00Z"}}
+	if err := WritePinned(path, want); err != nil {
+		t.Fatal(err)
+	}
+	keys, err = ReadPinned(path)
+	if err != nil || !reflect.DeepEqual(keys, want) {
+		t.Fatalf("ReadPinned() = %v, %v, want %v", keys, err, want)
+	}
+}
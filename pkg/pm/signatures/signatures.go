@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"math/big"
 	"sort"
+	"strings"
+	"time"
 	"unicode/utf8"
 
 	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
@@ -25,7 +27,7 @@ type ecdsaSignature struct {
 }
 
 type key struct {
-	Expires string `json:"expires"`
+	Expires string `json:"expires"` // RFC 3339, empty while the key is current
 	Type    string `json:"type"`
 	KeyID   string `json:"keyid"`
 	PubKey  string `json:"pubkey"`
@@ -34,13 +36,20 @@ type key struct {
 // Keys is the set of trusted public keys from keys.json.
 type Keys []key
 
+type trustedKey struct {
+	pub     *ecdsa.PublicKey
+	expires time.Time // zero if the key doesn't expire
+}
+
 type Verifier struct {
-	keys map[string]*ecdsa.PublicKey
+	keys map[string]trustedKey
+	now  func() time.Time
 }
 
-// New returns a Verifier backed by keys.
+// New returns a Verifier backed by keys. Keys with an unsupported type, or
+// whose public key or expiry can't be parsed, are not trusted.
 func New(keys Keys) *Verifier {
-	parsed := make(map[string]*ecdsa.PublicKey, len(keys))
+	parsed := make(map[string]trustedKey, len(keys))
 	for _, k := range keys {
 		if k.Type != signingAlgorithm {
 			continue
@@ -49,34 +58,108 @@ func New(keys Keys) *Verifier {
 		if err != nil {
 			continue
 		}
-		parsed[k.KeyID] = pub
+		var expires time.Time
+		if k.Expires != "" {
+			if expires, err = time.Parse(time.RFC3339, k.Expires); err != nil {
+				continue
+			}
+		}
+		parsed[k.KeyID] = trustedKey{pub: pub, expires: expires}
 	}
-	return &Verifier{keys: parsed}
+	return &Verifier{keys: parsed, now: time.Now}
+}
+
+// RotatedKeyError is returned when a manifest is only signed by keys that
+// have expired, i.e. were rotated out of keys.json.
+type RotatedKeyError struct {
+	KeyIDs  []string
+	Expired []time.Time
 }
 
-// Verify checks the manifest's signature against the trusted keys.
+func (e *RotatedKeyError) Error() string {
+	keys := make([]string, len(e.KeyIDs))
+	for i, id := range e.KeyIDs {
+		keys[i] = fmt.Sprintf("%s (expired %s)", id, e.Expired[i].Format(time.DateOnly))
+	}
+	return "signed only by rotated-out " + pluralKeys(len(keys)) + " " + strings.Join(keys, ", ") +
+		"; the registry has to re-sign this version with a current key"
+}
+
+// Verify checks the manifest's signatures against the trusted keys. It
+// passes when any signature is valid under a trusted key that has not
+// expired. A signature's time is not part of the signed payload, so it can't
+// vouch for a signature made before the key expired.
 func (v *Verifier) Verify(m *manifest.Package) error {
 	sigs := m.Dist.Signatures
 	if len(sigs) == 0 {
 		return errors.New("no signatures found")
 	}
 
-	pub, ok := v.keys[sigs[0].KeyID]
-	if !ok {
-		return fmt.Errorf("no trusted key for KeyID %s", sigs[0].KeyID)
+	msg, err := manifestPayload(m)
+	if err != nil {
+		return err
 	}
 
-	var deps map[string]string
-	if m.Dependencies != nil {
-		deps = *m.Dependencies
+	var errs []error
+	rotated := &RotatedKeyError{}
+	for _, sig := range sigs {
+		k, ok := v.keys[sig.KeyID]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no trusted key for KeyID %s", sig.KeyID))
+			continue
+		}
+		if !k.current(v.now()) {
+			rotated.KeyIDs = append(rotated.KeyIDs, sig.KeyID)
+			rotated.Expired = append(rotated.Expired, k.expires)
+			continue
+		}
+		if err := verifyECDSA(k.pub, sig.Sig, msg); err != nil {
+			errs = append(errs, fmt.Errorf("KeyID %s: %w", sig.KeyID, err))
+			continue
+		}
+		return nil
 	}
 
-	msg, err := payload(m.Name, m.Version, m.Dist.Digest, deps)
+	if len(errs) == 0 {
+		return rotated
+	}
+	for i, id := range rotated.KeyIDs {
+		errs = append(errs, fmt.Errorf("KeyID %s: key expired on %s", id, rotated.Expired[i].Format(time.DateOnly)))
+	}
+	return errors.Join(errs...)
+}
+
+// SignedBy returns the IDs of the trusted keys that validly signed the
+// manifest.
+func (v *Verifier) SignedBy(m *manifest.Package) []string {
+	msg, err := manifestPayload(m)
 	if err != nil {
-		return err
+		return nil
+	}
+
+	var ids []string
+	for _, sig := range m.Dist.Signatures {
+		k, ok := v.keys[sig.KeyID]
+		if !ok || !k.current(v.now()) {
+			continue
+		}
+		if verifyECDSA(k.pub, sig.Sig, msg) == nil {
+			ids = append(ids, sig.KeyID)
+		}
 	}
+	return ids
+}
 
-	return verifyECDSA(pub, sigs[0].Sig, msg)
+// current reports whether the key has not expired at now.
+func (k trustedKey) current(now time.Time) bool {
+	return k.expires.IsZero() || now.Before(k.expires)
+}
+
+func pluralKeys(n int) string {
+	if n == 1 {
+		return "key"
+	}
+	return "keys"
 }
 
 func parseECDSAKey(pubKeyBase64 string) (*ecdsa.PublicKey, error) {
@@ -115,6 +198,14 @@ func verifyECDSA(pub *ecdsa.PublicKey, signatureBase64 string, msg []byte) error
 	return nil
 }
 
+func manifestPayload(m *manifest.Package) ([]byte, error) {
+	var deps map[string]string
+	if m.Dependencies != nil {
+		deps = *m.Dependencies
+	}
+	return payload(m.Name, m.Version, m.Dist.Digest, deps)
+}
+
 // payload builds the message the registry signs:
 //
 //	name:version:digest                 (no dependencies)
@@ -1,6 +1,19 @@
 package signatures
 
-import "testing"
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+)
 
 func TestCanonicalDependencies(t *testing.T) {
 	cases := []struct {
@@ -63,3 +76,122 @@ func TestPayload(t *testing.T) {
 		}
 	})
 }
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	current, currentKey := newTestKey(t, "SHA256:current", "")
+	rotated, rotatedKey := newTestKey(t, "SHA256:rotated", "2026-01-01T00:00:00.000Z")
+	verifier := New(Keys{currentKey, rotatedKey})
+	verifier.now = func() time.Time { return now }
+
+	pkg := func(sigs ...manifest.Signature) *manifest.Package {
+		return &manifest.Package{
+			Name:    "akismet",
+			Version: "5.4.0",
+			Dist:    manifest.Dist{Digest: "sha256:abc", Signatures: sigs},
+		}
+	}
+	msg, err := payload("akismet", "5.4.0", "sha256:abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		sigs    []manifest.Signature
+		wantErr string
+	}{
+		{name: "current key", sigs: []manifest.Signature{sign(t, current, "SHA256:current", msg)}},
+		{name: "no signatures", wantErr: "no signatures found"},
+		{name: "unknown key", sigs: []manifest.Signature{{KeyID: "SHA256:other", Sig: "AA=="}}, wantErr: "no trusted key for KeyID SHA256:other"},
+		{
+			name:    "rotated key",
+			sigs:    []manifest.Signature{sign(t, rotated, "SHA256:rotated", msg)},
+			wantErr: "signed only by rotated-out key SHA256:rotated (expired 2026-01-01)",
+		},
+		{
+			name: "any valid signature passes",
+			sigs: []manifest.Signature{
+				{KeyID: "SHA256:other", Sig: "AA=="},
+				sign(t, rotated, "SHA256:rotated", msg),
+				sign(t, current, "SHA256:current", msg),
+			},
+		},
+		{
+			name:    "signature by the wrong key",
+			sigs:    []manifest.Signature{sign(t, rotated, "SHA256:current", msg)},
+			wantErr: "KeyID SHA256:current: signature verification failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifier.Verify(pkg(tt.sigs...))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignedBy(t *testing.T) {
+	first, firstKey := newTestKey(t, "SHA256:first", "")
+	second, secondKey := newTestKey(t, "SHA256:second", "")
+	verifier := New(Keys{firstKey, secondKey})
+
+	msg, err := payload("akismet", "5.4.0", "sha256:abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &manifest.Package{
+		Name:    "akismet",
+		Version: "5.4.0",
+		Dist: manifest.Dist{Digest: "sha256:abc", Signatures: []manifest.Signature{
+			sign(t, first, "SHA256:first", msg),
+			sign(t, first, "SHA256:second", msg),
+			{KeyID: "SHA256:other", Sig: "AA=="},
+			sign(t, second, "SHA256:second", msg),
+		}},
+	}
+
+	got := verifier.SignedBy(m)
+	if want := []string{"SHA256:first", "SHA256:second"}; !slices.Equal(got, want) {
+		t.Errorf("SignedBy() = %v, want %v", got, want)
+	}
+}
+
+func TestNewSkipsUnparsableExpiry(t *testing.T) {
+	_, k := newTestKey(t, "SHA256:bad", "next tuesday")
+	if v := New(Keys{k}); len(v.keys) != 0 {
+		t.Fatalf("New() trusted a key with an unparsable expiry")
+	}
+}
+
+func newTestKey(t *testing.T, keyID, expires string) (*ecdsa.PrivateKey, key) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, key{Expires: expires, Type: signingAlgorithm, KeyID: keyID, PubKey: base64.StdEncoding.EncodeToString(der)}
+}
+
+func sign(t *testing.T, priv *ecdsa.PrivateKey, keyID string, msg []byte) manifest.Signature {
+	t.Helper()
+	hash := sha256.Sum256(msg)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifest.Signature{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}
+}
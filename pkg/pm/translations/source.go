@@ -0,0 +1,100 @@
+package translations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.wpm.so/cli/pkg/api"
+	"go.wpm.so/cli/pkg/pm/mirror"
+	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+// ErrNotFound is returned by Source.Resolve when no pack exists for the
+// requested package version and locale.
+var ErrNotFound = errors.New("no translation pack available")
+
+// Release describes a translation pack resolved from a Source.
+type Release struct {
+	Source string
+	Digest string // sha256:<base64>, same encoding as manifest.Dist.Digest
+}
+
+// Source resolves and downloads translation packs.
+type Source interface {
+	// Resolve looks up the pack and returns its expected digest.
+	Resolve(ctx context.Context, pack Pack) (*Release, error)
+	// Open streams the pack tarball. The caller must close the reader.
+	Open(ctx context.Context, pack Pack, release *Release) (io.ReadCloser, error)
+}
+
+// NewSource returns the Source configured by cfg, falling back to the
+// registry when cfg is nil or has no URL.
+func NewSource(cfg *types.Translations, client registry.Client, httpClient *http.Client) Source {
+	if cfg != nil && cfg.URL != "" {
+		return &mirrorSource{template: cfg.URL, client: httpClient}
+	}
+	return &registrySource{client: client}
+}
+
+type registrySource struct {
+	client registry.Client
+}
+
+func (s *registrySource) Resolve(ctx context.Context, pack Pack) (*Release, error) {
+	t, err := s.client.GetTranslation(ctx, pack.Name, pack.Version, pack.Locale)
+	if err != nil {
+		if httpErr, ok := errors.AsType[*api.HTTPError](err); ok && httpErr.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", pack, err)
+	}
+	if t.Version != pack.Version || t.Locale != pack.Locale {
+		return nil, fmt.Errorf("registry returned %s@%s [%s], expected %s", t.Name, t.Version, t.Locale, pack)
+	}
+
+	return &Release{
+		Source: "registry",
+		Digest: t.Dist.Digest,
+	}, nil
+}
+
+func (s *registrySource) Open(ctx context.Context, pack Pack, _ *Release) (io.ReadCloser, error) {
+	return s.client.DownloadTarball(ctx, "/"+pack.Name+"/"+pack.Version+"/translations/"+pack.Locale+".tar.zst")
+}
+
+// mirrorSource fetches packs from a URL template, see package mirror for the
+// checksum sidecar format.
+type mirrorSource struct {
+	template string
+	client   *http.Client
+}
+
+func (s *mirrorSource) Resolve(ctx context.Context, pack Pack) (*Release, error) {
+	tarballURL := mirror.Expand(s.template, map[string]string{
+		"type":    string(pack.Type),
+		"name":    pack.Name,
+		"version": pack.Version,
+		"locale":  pack.Locale,
+	})
+
+	digest, err := mirror.Digest(ctx, s.client, tarballURL)
+	if errors.Is(err, mirror.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checksum for %s: %w", tarballURL, err)
+	}
+
+	return &Release{
+		Source: tarballURL,
+		Digest: digest,
+	}, nil
+}
+
+func (s *mirrorSource) Open(ctx context.Context, _ Pack, release *Release) (io.ReadCloser, error) {
+	return mirror.Fetch(ctx, s.client, release.Source)
+}
@@ -0,0 +1,340 @@
+// Package translations installs WordPress language packs for installed
+// plugins and themes into <content-dir>/languages.
+package translations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"go.wpm.so/cli/pkg/archive"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/pm/wpmlock"
+)
+
+const maxPackFiles = 1024
+
+var jsonSuffixRegex = regexp.MustCompile(`^-[0-9a-f]{32}\.json$`)
+
+// Pack identifies one locale's translations for one installed package version.
+type Pack struct {
+	Name    string
+	Version string
+	Type    types.PackageType
+	Locale  string
+}
+
+func (p Pack) String() string {
+	return p.Name + "@" + p.Version + " [" + p.Locale + "]"
+}
+
+// Change reports a pack that was added to or removed from the languages directory.
+type Change struct {
+	Pack    Pack
+	Removed bool
+}
+
+// Locked maps package name to locale to the installed pack, as stored in wpm.lock.
+type Locked = map[string]map[string]wpmlock.LockTranslation
+
+// Syncer brings the languages directory in line with the wanted packs.
+type Syncer struct {
+	contentDir  string
+	source      Source
+	concurrency int
+}
+
+// New returns a Syncer installing packs from source under contentDir.
+func New(contentDir string, source Source, concurrency int) *Syncer {
+	if concurrency <= 0 {
+		concurrency = 16
+	}
+	return &Syncer{contentDir: contentDir, source: source, concurrency: concurrency}
+}
+
+// Sync installs every wanted pack that is missing or locked at another
+// version, and removes locked packs that are no longer wanted. Packs the
+// source doesn't have are skipped.
+//
+// The returned map reflects what is on disk even when an error is returned:
+// packs that failed to install keep their previous entry and files.
+func (s *Syncer) Sync(ctx context.Context, wanted []Pack, locked Locked, progressFn func(Change)) (Locked, int, error) {
+	result := make(Locked)
+	var mu sync.Mutex
+	changes := 0
+
+	record := func(pack Pack, entry *wpmlock.LockTranslation, change *Change) {
+		mu.Lock()
+		defer mu.Unlock()
+		if entry != nil {
+			if result[pack.Name] == nil {
+				result[pack.Name] = make(map[string]wpmlock.LockTranslation)
+			}
+			result[pack.Name][pack.Locale] = *entry
+		}
+		if change != nil {
+			changes++
+			if progressFn != nil {
+				progressFn(*change)
+			}
+		}
+	}
+
+	wantedSet := make(map[string]map[string]bool)
+	for _, p := range wanted {
+		if wantedSet[p.Name] == nil {
+			wantedSet[p.Name] = make(map[string]bool)
+		}
+		wantedSet[p.Name][p.Locale] = true
+	}
+
+	var errs []error
+
+	// Drop packs whose package or locale is gone.
+	for name, locales := range locked {
+		for locale, entry := range locales {
+			if wantedSet[name][locale] {
+				continue
+			}
+			pack := Pack{Name: name, Version: entry.Version, Locale: locale}
+			if err := s.removeFiles(entry.Files); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", pack, err))
+				record(pack, &entry, nil)
+				continue
+			}
+			record(pack, nil, &Change{Pack: pack, Removed: true})
+		}
+	}
+
+	g := new(errgroup.Group)
+	g.SetLimit(s.concurrency)
+
+	for _, pack := range wanted {
+		old, hasOld := locked[pack.Name][pack.Locale]
+		if hasOld && old.Version == pack.Version && s.filesExist(old.Files) {
+			record(pack, &old, nil)
+			continue
+		}
+
+		g.Go(func() error {
+			entry, err := s.install(ctx, pack)
+			if errors.Is(err, ErrNotFound) {
+				// No pack for this version; anything left over belongs to an older one.
+				if hasOld {
+					if err := s.removeFiles(old.Files); err != nil {
+						record(pack, &old, nil)
+						return fmt.Errorf("failed to remove %s: %w", pack, err)
+					}
+					record(pack, nil, &Change{Pack: Pack{Name: pack.Name, Version: old.Version, Locale: pack.Locale}, Removed: true})
+				}
+				return nil
+			}
+			if err != nil {
+				if hasOld {
+					record(pack, &old, nil)
+				}
+				return fmt.Errorf("failed to install %s: %w", pack, err)
+			}
+
+			if hasOld {
+				var stale []string
+				for _, f := range old.Files {
+					if !slices.Contains(entry.Files, f) {
+						stale = append(stale, f)
+					}
+				}
+				_ = s.removeFiles(stale)
+			}
+
+			record(pack, entry, &Change{Pack: pack})
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return result, changes, errors.Join(errs...)
+}
+
+// languagesDir returns where WordPress looks up translations for a package type.
+func (s *Syncer) languagesDir(t types.PackageType) (string, error) {
+	switch t {
+	case types.TypePlugin:
+		return filepath.Join(s.contentDir, "languages", "plugins"), nil
+	case types.TypeTheme:
+		return filepath.Join(s.contentDir, "languages", "themes"), nil
+	default:
+		return "", fmt.Errorf("unknown package type %q", t)
+	}
+}
+
+func (s *Syncer) install(ctx context.Context, pack Pack) (*wpmlock.LockTranslation, error) {
+	destDir, err := s.languagesDir(pack.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.source.Resolve(ctx, pack)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := s.source.Open(ctx, pack, release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	tmpDir := filepath.Join(s.contentDir, ".tmp")
+	//nolint:gosec // Dir perms are intentionally permissive here.
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create tmp directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(tmpDir, "l10n-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	hasher := sha256.New()
+	stream := io.TeeReader(rc, hasher)
+
+	if err := archive.Untar(ctx, stream, staging, &archive.TarOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to extract translation pack: %w", err)
+	}
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return nil, fmt.Errorf("failed to drain download stream: %w", err)
+	}
+
+	cleanDigest := strings.TrimPrefix(release.Digest, "sha256:")
+	calculated := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+	if calculated != cleanDigest {
+		return nil, fmt.Errorf("digest mismatch: expected %s, got %s", cleanDigest, calculated)
+	}
+
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return nil, errors.New("invalid translation pack: expected exactly one root directory")
+	}
+	extracted := filepath.Join(staging, entries[0].Name())
+
+	files, err := packFiles(extracted, pack)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // Dir perms are intentionally permissive here.
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create languages directory: %w", err)
+	}
+
+	rel, err := filepath.Rel(s.contentDir, destDir)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make([]string, 0, len(files))
+	for _, f := range files {
+		if err := os.Rename(filepath.Join(extracted, f), filepath.Join(destDir, f)); err != nil {
+			return nil, fmt.Errorf("failed to install %s: %w", f, err)
+		}
+		locked = append(locked, filepath.ToSlash(filepath.Join(rel, f)))
+	}
+
+	return &wpmlock.LockTranslation{
+		Version: pack.Version,
+		Source:  release.Source,
+		Digest:  release.Digest,
+		Files:   locked,
+	}, nil
+}
+
+// packFiles lists the files of an extracted pack. Packs are flat and every
+// file must be named after the package and locale, so one pack can never
+// overwrite another package's translations.
+func packFiles(dir string, pack Pack) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > maxPackFiles {
+		return nil, fmt.Errorf("invalid translation pack: more than %d files", maxPackFiles)
+	}
+
+	prefix := pack.Name + "-" + pack.Locale
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() {
+			return nil, fmt.Errorf("invalid translation pack: %s is not a regular file", name)
+		}
+		if rest, ok := strings.CutPrefix(name, prefix); !ok || !validSuffix(rest) {
+			return nil, fmt.Errorf("invalid translation pack: %s does not belong to %s", name, pack)
+		}
+		files = append(files, name)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// validSuffix reports whether rest, the part of a file name after
+// "<name>-<locale>", is one WordPress uses: a file extension such as ".mo" or
+// ".l10n.php", or "-<md5>.json" for script translations.
+func validSuffix(rest string) bool {
+	if strings.HasPrefix(rest, ".") {
+		return len(rest) > 1
+	}
+	return jsonSuffixRegex.MatchString(rest)
+}
+
+// resolve maps a lockfile path back under contentDir, refusing anything that
+// would escape the languages directory.
+func (s *Syncer) resolve(rel string) (string, bool) {
+	p := filepath.FromSlash(rel)
+	if !filepath.IsLocal(p) || !strings.HasPrefix(filepath.ToSlash(p), "languages/") {
+		return "", false
+	}
+	return filepath.Join(s.contentDir, p), true
+}
+
+func (s *Syncer) filesExist(files []string) bool {
+	for _, f := range files {
+		p, ok := s.resolve(f)
+		if !ok {
+			return false
+		}
+		if _, err := os.Stat(p); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Syncer) removeFiles(files []string) error {
+	for _, f := range files {
+		p, ok := s.resolve(f)
+		if !ok {
+			continue
+		}
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
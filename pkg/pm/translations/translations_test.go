@@ -0,0 +1,160 @@
+package translations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.wpm.so/cli/pkg/archive"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+// addPack packs files into the mirror at the path the
+// "{type}/{name}/{version}/{locale}.tar.zst" template resolves to.
+func addPack(t *testing.T, mirrorDir string, pack Pack, files map[string]string) {
+	t.Helper()
+
+	src := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tb, err := archive.Tar(context.Background(), src, &archive.TarOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := tb.Reader()
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(mirrorDir, string(pack.Type), pack.Name, pack.Version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tarball := filepath.Join(dir, pack.Locale+".tar.zst")
+	if err := os.WriteFile(tarball, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	if err := os.WriteFile(tarball+".sha256", []byte(hex.EncodeToString(sum[:])+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	mirrorDir := t.TempDir()
+	contentDir := t.TempDir()
+	langDir := filepath.Join(contentDir, "languages", "plugins")
+
+	akismetV1 := Pack{Name: "akismet", Version: "5.3.1", Type: types.TypePlugin, Locale: "de_DE"}
+	akismetV2 := Pack{Name: "akismet", Version: "5.4.0", Type: types.TypePlugin, Locale: "de_DE"}
+	akismetFR := Pack{Name: "akismet", Version: "5.3.1", Type: types.TypePlugin, Locale: "fr_FR"}
+	noPack := Pack{Name: "hello-dolly", Version: "1.7.2", Type: types.TypePlugin, Locale: "de_DE"}
+
+	addPack(t, mirrorDir, akismetV1, map[string]string{
+		"akismet-de_DE.mo": "mo",
+		"akismet-de_DE.po": "po",
+		"akismet-de_DE-0123456789abcdef0123456789abcdef.json": "{}",
+		"akismet-de_DE.l10n.php":                              "<?php",
+		"akismet-de_DE_formal.mo":                             "x", // different locale, must be rejected
+	})
+	addPack(t, mirrorDir, akismetV2, map[string]string{
+		"akismet-de_DE.mo":       "mo2",
+		"akismet-de_DE.l10n.php": "<?php // v2",
+	})
+	addPack(t, mirrorDir, akismetFR, map[string]string{
+		"akismet-fr_FR.mo": "mo",
+	})
+
+	template := "file://" + filepath.ToSlash(mirrorDir) + "/{type}/{name}/{version}/{locale}.tar.zst"
+	syncer := New(contentDir, NewSource(&types.Translations{URL: template}, nil, nil), 4)
+
+	// A pack with a file from another locale is refused outright.
+	locked, changes, err := syncer.Sync(ctx, []Pack{akismetV1}, nil, nil)
+	if err == nil {
+		t.Fatal("Sync accepted a pack containing another locale's file")
+	}
+	if changes != 0 || len(locked) != 0 {
+		t.Fatalf("failed pack was recorded: changes=%d locked=%v", changes, locked)
+	}
+
+	addPack(t, mirrorDir, akismetV1, map[string]string{
+		"akismet-de_DE.mo": "mo",
+		"akismet-de_DE.po": "po",
+		"akismet-de_DE-0123456789abcdef0123456789abcdef.json": "{}",
+		"akismet-de_DE.l10n.php":                              "<?php",
+	})
+
+	locked, changes, err = syncer.Sync(ctx, []Pack{akismetV1, akismetFR, noPack}, nil, nil)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if changes != 2 {
+		t.Fatalf("changes = %d, want 2", changes)
+	}
+	if _, ok := locked["hello-dolly"]; ok {
+		t.Fatal("recorded a pack the source does not have")
+	}
+	wantFiles := []string{
+		"languages/plugins/akismet-de_DE-0123456789abcdef0123456789abcdef.json",
+		"languages/plugins/akismet-de_DE.l10n.php",
+		"languages/plugins/akismet-de_DE.mo",
+		"languages/plugins/akismet-de_DE.po",
+	}
+	if got := locked["akismet"]["de_DE"].Files; !slices.Equal(got, wantFiles) {
+		t.Fatalf("locked files = %v, want %v", got, wantFiles)
+	}
+
+	// Nothing to do when the lock matches the disk.
+	locked, changes, err = syncer.Sync(ctx, []Pack{akismetV1, akismetFR}, locked, nil)
+	if err != nil || changes != 0 {
+		t.Fatalf("resync: changes=%d err=%v", changes, err)
+	}
+
+	// Upgrading the package swaps the pack and drops files the new one lacks;
+	// dropping a locale removes its files.
+	locked, _, err = syncer.Sync(ctx, []Pack{akismetV2}, locked, nil)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := locked["akismet"]["de_DE"].Version; got != "5.4.0" {
+		t.Fatalf("locked version = %s, want 5.4.0", got)
+	}
+	if exists(filepath.Join(langDir, "akismet-de_DE.po")) {
+		t.Error("stale .po from the previous version was left behind")
+	}
+	if exists(filepath.Join(langDir, "akismet-fr_FR.mo")) {
+		t.Error("fr_FR pack was not removed")
+	}
+	if b, _ := os.ReadFile(filepath.Join(langDir, "akismet-de_DE.mo")); string(b) != "mo2" {
+		t.Errorf("de_DE .mo = %q, want the 5.4.0 file", b)
+	}
+
+	// Uninstalling the package removes its packs.
+	locked, _, err = syncer.Sync(ctx, nil, locked, nil)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(locked) != 0 {
+		t.Fatalf("locked = %v, want empty", locked)
+	}
+	if exists(filepath.Join(langDir, "akismet-de_DE.mo")) {
+		t.Error("pack files remain after uninstall")
+	}
+}
@@ -0,0 +1,107 @@
+package wpcore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.wpm.so/cli/pkg/pm/mirror"
+	"go.wpm.so/cli/pkg/pm/registry"
+	"go.wpm.so/cli/pkg/pm/signatures"
+	"go.wpm.so/cli/pkg/pm/wpmjson/manifest"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+// DefaultPackage is the registry package core releases are published under.
+const DefaultPackage = "wordpress"
+
+// Release describes a single core release resolved from a Source.
+type Release struct {
+	Version    string
+	Source     string
+	Digest     string // sha256:<base64>, same encoding as manifest.Dist.Digest
+	Signatures []manifest.Signature
+}
+
+// Source resolves and downloads WordPress core releases.
+type Source interface {
+	// Resolve looks up the release for version and returns its expected digest.
+	Resolve(ctx context.Context, version string) (*Release, error)
+	// Open streams the release tarball. The caller must close the reader.
+	Open(ctx context.Context, release *Release) (io.ReadCloser, error)
+}
+
+// NewSource returns the Source configured by cfg, falling back to the
+// DefaultPackage on the registry when cfg is nil or empty.
+func NewSource(cfg *types.Core, client registry.Client, httpClient *http.Client) Source {
+	if cfg != nil && cfg.URL != "" {
+		return &mirrorSource{template: cfg.URL, client: httpClient}
+	}
+
+	name := DefaultPackage
+	if cfg != nil && cfg.Package != "" {
+		name = cfg.Package
+	}
+	return &registrySource{name: name, client: client}
+}
+
+type registrySource struct {
+	name   string
+	client registry.Client
+}
+
+func (s *registrySource) Resolve(ctx context.Context, version string) (*Release, error) {
+	keys, err := s.client.GetKeysJson(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	pkg, err := s.client.GetPackageManifest(ctx, s.name, version, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s@%s: %w", s.name, version, err)
+	}
+	if pkg.Version != version {
+		return nil, fmt.Errorf("registry returned %s@%s, expected %s", s.name, pkg.Version, version)
+	}
+	if err := signatures.New(keys).Verify(pkg); err != nil {
+		return nil, fmt.Errorf("signature verification failed for %s@%s: %w", s.name, version, err)
+	}
+
+	return &Release{
+		Version:    pkg.Version,
+		Source:     "registry:" + s.name,
+		Digest:     pkg.Dist.Digest,
+		Signatures: pkg.Dist.Signatures,
+	}, nil
+}
+
+func (s *registrySource) Open(ctx context.Context, release *Release) (io.ReadCloser, error) {
+	return s.client.DownloadTarball(ctx, "/"+s.name+"/"+release.Version+".tar.zst")
+}
+
+// mirrorSource fetches tarballs from a URL template, see package mirror for
+// the checksum sidecar format.
+type mirrorSource struct {
+	template string
+	client   *http.Client
+}
+
+func (s *mirrorSource) Resolve(ctx context.Context, version string) (*Release, error) {
+	tarballURL := mirror.Expand(s.template, map[string]string{"version": version})
+
+	digest, err := mirror.Digest(ctx, s.client, tarballURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checksum for %s: %w", tarballURL, err)
+	}
+
+	return &Release{
+		Version: version,
+		Source:  tarballURL,
+		Digest:  digest,
+	}, nil
+}
+
+func (s *mirrorSource) Open(ctx context.Context, release *Release) (io.ReadCloser, error) {
+	return mirror.Fetch(ctx, s.client, release.Source)
+}
@@ -0,0 +1,282 @@
+// Package wpcore installs and verifies WordPress core in a project root.
+package wpcore
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.wpm.so/cli/pkg/archive"
+)
+
+// Mismatch describes an installed core file that differs from the release.
+type Mismatch struct {
+	Path   string
+	Reason string
+}
+
+// preserved lists top-level entries core installs never touch, in addition
+// to the project's content directory.
+var preserved = map[string]bool{
+	"wp-content":    true,
+	"wp-config.php": true,
+	"wpm.json":      true,
+	"wpm.lock":      true,
+	".wpm":          true,
+}
+
+// isPreserved reports whether the top-level entry name belongs to the project
+// rather than to core.
+func isPreserved(name, contentDir string) bool {
+	if preserved[strings.ToLower(name)] {
+		return true
+	}
+	first, _, _ := strings.Cut(filepath.ToSlash(filepath.Clean(contentDir)), "/")
+	return name == first
+}
+
+// rename is os.Rename, replaced in tests to make a swap fail halfway.
+var rename = os.Rename
+
+// Install extracts the core tarball read from r into root, the directory core
+// lives in, verifying it against digest. contentDir is relative to root.
+//
+// Core files are swapped in one top-level entry at a time, since root is
+// shared with the content directory, wp-config.php and wpm's own files, which
+// are left untouched. If a swap fails, the entries already swapped are put
+// back, so root holds either the old core or the new one.
+func Install(ctx context.Context, root, contentDir string, r io.Reader, digest string) (err error) {
+	//nolint:gosec // Dir perms are intentionally permissive here.
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return fmt.Errorf("failed to create core directory: %w", err)
+	}
+
+	tmpDir := filepath.Join(root, contentDir, ".tmp")
+	//nolint:gosec // Dir perms are intentionally permissive here.
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(tmpDir, "core-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	keepStaging := false
+	defer func() {
+		if !keepStaging {
+			_ = os.RemoveAll(staging)
+		}
+	}()
+
+	hasher := sha256.New()
+	stream := io.TeeReader(r, hasher)
+
+	if err := archive.Untar(ctx, stream, staging, &archive.TarOptions{}); err != nil {
+		return fmt.Errorf("failed to extract core tarball: %w", err)
+	}
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return fmt.Errorf("failed to drain download stream: %w", err)
+	}
+	if err := checkDigest(digest, hasher.Sum(nil)); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return errors.New("invalid core tarball: expected exactly one root directory")
+	}
+	extracted := filepath.Join(staging, entries[0].Name())
+
+	files, err := os.ReadDir(extracted)
+	if err != nil {
+		return err
+	}
+
+	backupDir := filepath.Join(staging, "backup-"+entries[0].Name())
+	//nolint:gosec // Dir perms are intentionally permissive here.
+	if err := os.Mkdir(backupDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	var swapped []swap
+	for _, f := range files {
+		if isPreserved(f.Name(), contentDir) {
+			continue
+		}
+		s, err := replace(filepath.Join(extracted, f.Name()), filepath.Join(root, f.Name()), backupDir)
+		if s != nil {
+			swapped = append(swapped, *s)
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			if rbErr := rollback(swapped); rbErr != nil {
+				keepStaging = true
+				return fmt.Errorf("%w, AND failed to roll back: previous files preserved in %q: %v", err, backupDir, rbErr)
+			}
+			return fmt.Errorf("%w, rolled back", err)
+		}
+	}
+
+	return nil
+}
+
+// swap records a top-level entry of root that was replaced, and where the
+// entry it replaced was moved to, if there was one.
+type swap struct {
+	dst    string
+	backup string
+}
+
+// replace moves src over dst, moving an existing dst into backupDir first so
+// that the swap can be rolled back. It returns the swap to roll back, even
+// with an error once dst has been moved aside, or nil if root is unchanged.
+func replace(src, dst, backupDir string) (*swap, error) {
+	s := &swap{dst: dst}
+
+	if _, err := os.Lstat(dst); err == nil {
+		s.backup = filepath.Join(backupDir, filepath.Base(dst))
+		if err := rename(dst, s.backup); err != nil {
+			return nil, fmt.Errorf("failed to move %s aside: %w", dst, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err := rename(src, dst); err != nil {
+		return s, fmt.Errorf("failed to replace %s: %w", dst, err)
+	}
+	return s, nil
+}
+
+// rollback undoes swapped, newest first, removing the new entries and moving
+// the old ones back.
+func rollback(swapped []swap) error {
+	var errs []error
+	for i := len(swapped) - 1; i >= 0; i-- {
+		s := swapped[i]
+		if err := os.RemoveAll(s.dst); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if s.backup != "" {
+			if err := rename(s.backup, s.dst); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Verify compares every core file in the tarball read from r against the
+// copy installed under root and returns the files that are missing or
+// modified. The tarball itself is checked against digest.
+func Verify(ctx context.Context, root, contentDir string, r io.Reader, digest string) ([]Mismatch, error) {
+	hasher := sha256.New()
+	stream := io.TeeReader(r, hasher)
+
+	decompressed, err := archive.DecompressStream(stream)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = decompressed.Close() }()
+
+	var mismatches []Mismatch
+	tr := tar.NewReader(decompressed)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read core tarball: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		rel, ok := stripRoot(hdr.Name)
+		if !ok {
+			continue
+		}
+		first, _, _ := strings.Cut(rel, "/")
+		if isPreserved(first, contentDir) {
+			continue
+		}
+
+		want := sha256.New()
+		if _, err := io.Copy(want, tr); err != nil {
+			return nil, fmt.Errorf("failed to read %s from core tarball: %w", rel, err)
+		}
+
+		if m := compareFile(filepath.Join(root, filepath.FromSlash(rel)), want.Sum(nil)); m != "" {
+			mismatches = append(mismatches, Mismatch{Path: rel, Reason: m})
+		}
+	}
+
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return nil, fmt.Errorf("failed to drain download stream: %w", err)
+	}
+	if err := checkDigest(digest, hasher.Sum(nil)); err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
+
+// stripRoot drops the tarball's single root directory from name and reports
+// whether the remainder is a safe relative path.
+func stripRoot(name string) (string, bool) {
+	name = path.Clean(name)
+	_, rel, found := strings.Cut(name, "/")
+	if !found || rel == "" || !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", false
+	}
+	return rel, true
+}
+
+func compareFile(path string, want []byte) string {
+	f, err := os.Open(path) //nolint:gosec // path is root + a validated relative tarball entry
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "missing"
+		}
+		return err.Error()
+	}
+	defer func() { _ = f.Close() }()
+
+	got := sha256.New()
+	if _, err := io.Copy(got, f); err != nil {
+		return err.Error()
+	}
+	if !bytes.Equal(got.Sum(nil), want) {
+		return "modified"
+	}
+	return ""
+}
+
+func checkDigest(expected string, sum []byte) error {
+	cleanDigest := strings.TrimPrefix(expected, "sha256:")
+	calculated := base64.StdEncoding.EncodeToString(sum)
+	if calculated != cleanDigest {
+		return fmt.Errorf("digest mismatch: expected %s, got %s", cleanDigest, calculated)
+	}
+	return nil
+}
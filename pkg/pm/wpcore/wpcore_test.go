@@ -0,0 +1,238 @@
+package wpcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.wpm.so/cli/pkg/archive"
+	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// buildMirror packs a fake core release and returns a file:// URL template
+// pointing at it, with its sha256sum-style checksum sidecar alongside.
+func buildMirror(t *testing.T, version string, files map[string]string) string {
+	t.Helper()
+
+	src := t.TempDir()
+	for name, content := range files {
+		writeFile(t, filepath.Join(src, name), content)
+	}
+
+	tb, err := archive.Tar(context.Background(), src, &archive.TarOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := tb.Reader()
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mirror := t.TempDir()
+	tarball := filepath.Join(mirror, "wordpress-"+version+".tar.zst")
+	if err := os.WriteFile(tarball, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	writeFile(t, tarball+".sha256", hex.EncodeToString(sum[:])+"  wordpress-"+version+".tar.zst\n")
+
+	return "file://" + filepath.ToSlash(filepath.Join(mirror, "wordpress-{version}.tar.zst"))
+}
+
+func TestInstallAndVerify(t *testing.T) {
+	ctx := context.Background()
+
+	template := buildMirror(t, "6.9.0", map[string]string{
+		"index.php":                "<?php // core index",
+		"wp-includes/version.php":  "<?php\n$wp_version = '6.9';\n",
+		"wp-admin/admin.php":       "<?php // admin",
+		"wp-content/index.php":     "<?php // silence",
+		"wp-config-sample.php":     "<?php // sample",
+		"wp-config.php":            "<?php // should never ship",
+		"wp-includes/old-file.php": "<?php // old",
+	})
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "wp-config.php"), "<?php // site config")
+	writeFile(t, filepath.Join(root, "wp-content", "plugins", "akismet", "akismet.php"), "<?php // plugin")
+	writeFile(t, filepath.Join(root, "wp-includes", "version.php"), "<?php\n$wp_version = '6.8';\n")
+
+	source := NewSource(&types.Core{URL: template}, nil, nil)
+	release, err := source.Resolve(ctx, "6.9.0")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+
+	install := func() {
+		t.Helper()
+		rc, err := source.Open(ctx, release)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		defer rc.Close()
+		if err := Install(ctx, root, "wp-content", rc, release.Digest); err != nil {
+			t.Fatalf("Install: %v", err)
+		}
+	}
+	install()
+
+	if got := readFile(t, filepath.Join(root, "wp-includes", "version.php")); got != "<?php\n$wp_version = '6.9';\n" {
+		t.Errorf("version.php not replaced: %q", got)
+	}
+	if got := readFile(t, filepath.Join(root, "wp-config.php")); got != "<?php // site config" {
+		t.Errorf("wp-config.php was modified: %q", got)
+	}
+	if got := readFile(t, filepath.Join(root, "wp-content", "plugins", "akismet", "akismet.php")); got != "<?php // plugin" {
+		t.Errorf("wp-content was modified: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "wp-content", "index.php")); !os.IsNotExist(err) {
+		t.Errorf("wp-content/index.php from the tarball should not be installed, stat err = %v", err)
+	}
+
+	verify := func() []Mismatch {
+		t.Helper()
+		rc, err := source.Open(ctx, release)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		defer rc.Close()
+		mismatches, err := Verify(ctx, root, "wp-content", rc, release.Digest)
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		return mismatches
+	}
+
+	if m := verify(); len(m) != 0 {
+		t.Fatalf("fresh install reported mismatches: %v", m)
+	}
+
+	writeFile(t, filepath.Join(root, "wp-admin", "admin.php"), "<?php // tampered")
+	if err := os.Remove(filepath.Join(root, "index.php")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"wp-admin/admin.php": "modified", "index.php": "missing"}
+	m := verify()
+	if len(m) != len(want) {
+		t.Fatalf("Verify = %v, want %v", m, want)
+	}
+	for _, got := range m {
+		if want[got.Path] != got.Reason {
+			t.Errorf("Verify reported %s as %q, want %q", got.Path, got.Reason, want[got.Path])
+		}
+	}
+
+	// Reinstalling restores the tampered files.
+	install()
+	if m := verify(); len(m) != 0 {
+		t.Fatalf("reinstall left mismatches: %v", m)
+	}
+}
+
+func TestInstallRejectsDigestMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	template := buildMirror(t, "6.9.0", map[string]string{"index.php": "<?php"})
+	source := NewSource(&types.Core{URL: template}, nil, nil)
+	release, err := source.Resolve(ctx, "6.9.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := source.Open(ctx, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	root := t.TempDir()
+	err = Install(ctx, root, "wp-content", rc, "sha256:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	if err == nil {
+		t.Fatal("Install accepted a tarball with the wrong digest")
+	}
+	if _, err := os.Stat(filepath.Join(root, "index.php")); !os.IsNotExist(err) {
+		t.Errorf("index.php was installed despite digest mismatch")
+	}
+}
+
+func TestInstallRollsBack(t *testing.T) {
+	ctx := context.Background()
+
+	template := buildMirror(t, "6.9.0", map[string]string{
+		"index.php":               "<?php // new index",
+		"wp-admin/admin.php":      "<?php // new admin",
+		"wp-includes/version.php": "<?php\n$wp_version = '6.9';\n",
+	})
+	source := NewSource(&types.Core{URL: template}, nil, nil)
+	release, err := source.Resolve(ctx, "6.9.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	old := map[string]string{
+		"index.php":               "<?php // old index",
+		"wp-admin/admin.php":      "<?php // old admin",
+		"wp-includes/version.php": "<?php\n$wp_version = '6.8';\n",
+	}
+	for name, content := range old {
+		writeFile(t, filepath.Join(root, name), content)
+	}
+
+	// Fail moving the new wp-includes into place, after index.php and
+	// wp-admin have been swapped.
+	failed := false
+	rename = func(src, dst string) error {
+		if !failed && dst == filepath.Join(root, "wp-includes") {
+			failed = true
+			return errors.New("disk full")
+		}
+		return os.Rename(src, dst)
+	}
+	t.Cleanup(func() { rename = os.Rename })
+
+	rc, err := source.Open(ctx, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if err := Install(ctx, root, "wp-content", rc, release.Digest); err == nil {
+		t.Fatal("Install succeeded despite a failed swap")
+	}
+
+	for name, content := range old {
+		if got := readFile(t, filepath.Join(root, name)); got != content {
+			t.Errorf("%s = %q after rollback, want %q", name, got, content)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Join(root, "wp-content", ".tmp")); len(entries) != 0 {
+		t.Errorf("staging directory left behind: %v", entries)
+	}
+}
@@ -1,22 +1,64 @@
 package wpmignore
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/moby/patternmatcher/ignorefile"
 )
 
-// ReadWpmIgnore reads the .wpmignore file from the passed path and
-// returns the list of paths to exclude
+// DefaultPatterns are left out of every package: version control metadata,
+// dependencies, wpm's own state, editor and OS leftovers, and .env files.
+// A "!" pattern in .wpmignore brings any of them back.
+var DefaultPatterns = []string{
+	"**/.git",
+	"**/.svn",
+	"**/.hg",
+	"**/node_modules",
+	".wpm",
+	"**/.idea",
+	"**/.vscode",
+	"**/*.swp",
+	"**/*.swo",
+	"**/*~",
+	"**/.DS_Store",
+	"**/Thumbs.db",
+	"**/.env",
+	"**/.env.*",
+}
+
+// secretPatterns are file names that usually hold credentials.
+var secretPatterns = []string{
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"id_rsa",
+	"id_dsa",
+	"id_ecdsa",
+	"id_ed25519",
+	".htpasswd",
+	"auth.json",
+	"wp-config.php",
+}
+
+// ReadWpmIgnore returns the patterns of the files to leave out of the package
+// in path: DefaultPatterns, followed by those in .wpmignore, or in .gitignore
+// if there is no .wpmignore. The last matching pattern wins.
 func ReadWpmIgnore(path string) ([]string, error) {
-	var excludes []string
+	excludes := append([]string(nil), DefaultPatterns...)
 
 	f, err := os.Open(filepath.Join(path, ".wpmignore")) //nolint:gosec // .wpmignore is a constant relative to the caller-supplied path
 	switch {
 	case os.IsNotExist(err):
-		return excludes, nil
+		return readGitIgnore(path, excludes)
 	case err != nil:
 		return nil, err
 	}
@@ -26,5 +68,85 @@ func ReadWpmIgnore(path string) ([]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read .wpmignore: %w", err)
 	}
-	return patterns, nil
+	return append(excludes, patterns...), nil
+}
+
+// readGitIgnore appends the patterns of the .gitignore in path, if there is
+// one, to excludes.
+func readGitIgnore(path string, excludes []string) ([]string, error) {
+	f, err := os.Open(filepath.Join(path, ".gitignore")) //nolint:gosec // .gitignore is a constant relative to the caller-supplied path
+	switch {
+	case os.IsNotExist(err):
+		return excludes, nil
+	case err != nil:
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	patterns, err := gitIgnorePatterns(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+	return append(excludes, patterns...), nil
+}
+
+// gitIgnorePatterns reads gitignore patterns and rewrites them to the
+// .wpmignore syntax, where every pattern is relative to the package root: a
+// pattern without a slash, such as "*.log", matches at any depth in a
+// .gitignore, so it becomes "**/*.log".
+func gitIgnorePatterns(r io.Reader) ([]string, error) {
+	var patterns []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		if negate {
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		line = strings.TrimSuffix(line, "/")
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/"):
+			line = strings.TrimLeft(line, "/")
+		case !strings.Contains(line, "/"):
+			line = "**/" + line
+		}
+
+		if negate {
+			line = "!" + line
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// LooksLikeSecret reports whether the slash-separated path name is a file
+// that usually holds credentials, such as .env or a private key.
+func LooksLikeSecret(name string) bool {
+	base := strings.ToLower(path.Base(name))
+	for _, pattern := range secretPatterns {
+		if matched, _ := path.Match(pattern, base); matched {
+			return !isExample(base)
+		}
+	}
+	return false
+}
+
+// isExample reports whether base is a template for a secret file, such as
+// .env.example, which is safe to publish.
+func isExample(base string) bool {
+	for _, suffix := range []string{".example", ".sample", ".dist", ".template"} {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,74 @@
+package wpmignore
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGitIgnorePatterns(t *testing.T) {
+	gitignore := `# build output
+/vendor/
+*.log
+!keep.log
+docs/internal
+\#notes
+`
+	got, err := gitIgnorePatterns(strings.NewReader(gitignore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"vendor", "**/*.log", "!**/keep.log", "docs/internal", "**/#notes"}
+	if !slices.Equal(got, want) {
+		t.Errorf("gitIgnorePatterns() = %q, want %q", got, want)
+	}
+}
+
+func TestReadWpmIgnore(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ReadWpmIgnore(dir)
+	if err != nil || !slices.Equal(got, DefaultPatterns) {
+		t.Errorf("no ignore files: ReadWpmIgnore() = %q, %v; want the defaults", got, err)
+	}
+
+	write(".gitignore", "*.log\n")
+	got, _ = ReadWpmIgnore(dir)
+	if want := append(slices.Clone(DefaultPatterns), "**/*.log"); !slices.Equal(got, want) {
+		t.Errorf(".gitignore: ReadWpmIgnore() = %q, want %q", got, want)
+	}
+
+	write(".wpmignore", "!.env.example\n")
+	got, _ = ReadWpmIgnore(dir)
+	if want := append(slices.Clone(DefaultPatterns), "!.env.example"); !slices.Equal(got, want) {
+		t.Errorf(".wpmignore: ReadWpmIgnore() = %q, want %q", got, want)
+	}
+}
+
+func TestLooksLikeSecret(t *testing.T) {
+	tests := map[string]bool{
+		".env":                 true,
+		"config/.env.local":    true,
+		".env.example":         false,
+		"certs/server.pem":     true,
+		"keys/id_ed25519":      true,
+		"id_ed25519.pub":       false,
+		"auth.json":            true,
+		"wp-config.php":        true,
+		"wp-config-sample.php": false,
+		"src/plugin.php":       false,
+	}
+	for name, want := range tests {
+		if got := LooksLikeSecret(name); got != want {
+			t.Errorf("LooksLikeSecret(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
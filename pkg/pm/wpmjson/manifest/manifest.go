@@ -9,11 +9,12 @@ type Signature struct {
 
 // Dist struct to define the distribution metadata
 type Dist struct {
-	Digest       string      `json:"digest"`
-	Signatures   []Signature `json:"signatures"`
-	TotalFiles   int64       `json:"totalFiles"`
-	PackedSize   int64       `json:"packedSize"`
-	UnpackedSize int64       `json:"unpackedSize"`
+	Digest          string      `json:"digest"`
+	Signatures      []Signature `json:"signatures"`
+	AuthorSignature *Signature  `json:"authorSignature,omitempty"` // made by the author when publishing
+	TotalFiles      int64       `json:"totalFiles"`
+	PackedSize      int64       `json:"packedSize"`
+	UnpackedSize    int64       `json:"unpackedSize"`
 }
 
 // Package struct to define the package manifest in registry
@@ -37,4 +38,24 @@ type Package struct {
 	Wpm             string                  `json:"_wpm"`
 	Visibility      types.PackageVisibility `json:"visibility"`
 	Readme          string                  `json:"readme,omitempty"`
+	PublishedAt     string                  `json:"publishedAt,omitempty"` // when the registry accepted this version, RFC 3339
+}
+
+// Packument lists every published version of a package along with its
+// distribution tags.
+type Packument struct {
+	Name     string              `json:"name"`
+	DistTags map[string]string   `json:"dist-tags"`
+	Versions map[string]*Package `json:"versions"`
+}
+
+// Translation struct to define a translation pack manifest in registry
+//
+// A pack holds the .mo, .po, .json and .l10n.php files of one locale for one
+// package version.
+type Translation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Locale  string `json:"locale"`
+	Dist    Dist   `json:"dist"`
 }
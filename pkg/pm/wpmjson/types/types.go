@@ -56,11 +56,113 @@ type Runtime struct {
 	PHP string `json:"php,omitempty"`
 }
 
+// Core struct to define where WordPress core releases are fetched from
+//
+// Package names a registry package that publishes core releases, while URL
+// points at a mirror serving .tar.zst tarballs. URL may contain a {version}
+// placeholder and takes precedence over Package when both are set.
+//
+// Example:
+//
+//	"core": {
+//	    "url": "https://mirror.example.com/wordpress-{version}.tar.zst"
+//	}
+type Core struct {
+	Package string `json:"package,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Translations struct to define where translation packs are fetched from
+//
+// URL points at a mirror serving .tar.zst translation packs and may contain
+// {type}, {name}, {version} and {locale} placeholders. When unset, packs are
+// fetched from the registry.
+//
+// Example:
+//
+//	"translations": {
+//	    "url": "https://mirror.example.com/{type}/{name}/{version}/{locale}.tar.zst"
+//	}
+type Translations struct {
+	URL string `json:"url,omitempty"`
+}
+
+// AuthorKey struct to define a package author's public signing key
+//
+// PubKey is the base64 DER (PKIX) encoding of an ECDSA P-256 public key and
+// KeyID its "SHA256:" fingerprint, as printed by `wpm publish --sign`.
+//
+// Example:
+//
+//	"trusted-authors": [
+//	    {"keyid": "SHA256:jl3b...", "pubkey": "MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE..."}
+//	]
+type AuthorKey struct {
+	KeyID  string `json:"keyid"`
+	PubKey string `json:"pubkey"`
+}
+
+// Licenses struct to define the license policy for installed packages
+//
+// Entries are SPDX identifiers, or patterns such as "GPL-*". With Allow set,
+// only the listed licenses may be installed; Deny always wins. "UNKNOWN"
+// stands for packages that declare no license.
+//
+// Example:
+//
+//	"licenses": {
+//	    "allow": ["GPL-2.0-or-later", "GPL-3.0-or-later", "MIT"],
+//	    "deny": ["UNKNOWN"]
+//	}
+type Licenses struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// Policy struct to define the install policy for dependencies
+//
+// MaxUnpackedSize takes a size such as "20MB". ForbiddenFiles are glob
+// patterns, matched against the file name or, when they contain a "/", the
+// path inside the package. SignedBy lists registry or author key IDs of which
+// each package needs a valid signature; author keys must also be trusted in
+// "trusted-authors".
+//
+// Example:
+//
+//	"policy": {
+//	    "max-unpacked-size": "20MB",
+//	    "forbidden-files": ["*.phar", "*.exe"],
+//	    "forbid-executables": true,
+//	    "no-dependency-scripts": true,
+//	    "allowed-types": ["plugin"]
+//	}
+type Policy struct {
+	MaxUnpackedSize     string        `json:"max-unpacked-size,omitempty"`
+	ForbiddenFiles      []string      `json:"forbidden-files,omitempty"`
+	ForbidExecutables   bool          `json:"forbid-executables,omitempty"`
+	SignedBy            []string      `json:"signed-by,omitempty"`
+	NoDependencyScripts bool          `json:"no-dependency-scripts,omitempty"`
+	AllowedTypes        []PackageType `json:"allowed-types,omitempty"`
+}
+
 // PackageConfig struct to define the package configuration
 type PackageConfig struct {
-	BinDir     string   `json:"bin-dir,omitempty"`
-	ContentDir string   `json:"content-dir,omitempty"`
-	Runtime    *Runtime `json:"runtime,omitempty"`
+	BinDir         string        `json:"bin-dir,omitempty"`
+	ContentDir     string        `json:"content-dir,omitempty"`
+	PluginDir      string        `json:"plugin-dir,omitempty"`
+	ThemeDir       string        `json:"theme-dir,omitempty"`
+	Runtime        *Runtime      `json:"runtime,omitempty"`
+	Core           *Core         `json:"core,omitempty"`
+	Locales        []string      `json:"locales,omitempty"`
+	Translations   *Translations `json:"translations,omitempty"`
+	TrustedAuthors []AuthorKey   `json:"trusted-authors,omitempty"`
+	Licenses       *Licenses     `json:"licenses,omitempty"`
+	Policy         *Policy       `json:"policy,omitempty"`
+
+	// MinimumReleaseAge holds back versions published more recently than this,
+	// such as "72h" or "3d". Packages in MinimumReleaseAgeExclude are exempt.
+	MinimumReleaseAge        string   `json:"minimum-release-age,omitempty"`
+	MinimumReleaseAgeExclude []string `json:"minimum-release-age-exclude,omitempty"`
 }
 
 // Requires holds wp and php version constraints for a package
@@ -16,6 +16,9 @@ import (
 
 var packageNameRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 
+// localeRegex matches WordPress locale codes such as de, de_DE, de_DE_formal and pt_PT_ao90.
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(_[A-Z]{2}(_[a-z0-9]+)?)?$`)
+
 const unsafeStringMsg = "contains invalid control characters or invisible formatting"
 
 // MaxDependencies is the limit on entries in dependencies or devDependencies.
@@ -119,6 +122,33 @@ func IsValidHomepage(homepage string) error {
 	return nil
 }
 
+// IsValidSourceURL checks that a download source is an http, https, or file URL.
+func IsValidSourceURL(source string) error {
+	if len(source) < 8 || len(source) > 512 {
+		return errors.New("must be between 8 and 512 characters")
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return errors.New("must be a valid URL")
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return errors.New("URL must include a host")
+		}
+	case "file":
+		if u.Path == "" {
+			return errors.New("file URL must include a path")
+		}
+	default:
+		return errors.New("URL scheme must be http, https, or file")
+	}
+
+	return nil
+}
+
 // IsValidConstraint checks if the version constraint string is valid.
 func IsValidConstraint(v string) error {
 	if v == "" {
@@ -187,6 +217,50 @@ func ValidateTags(tags []string) error {
 	return errs.Err()
 }
 
+// ValidateFiles checks that each entry in the files allowlist is a valid glob
+// pattern or relative path that stays inside the package.
+func ValidateFiles(files []string) error {
+	var errs ErrorList
+	for i, f := range files {
+		field := fmt.Sprintf("files[%d]", i)
+
+		if err := IsValidProjectRelPath(f); err != nil {
+			errs.Add(field, err)
+			continue
+		}
+		if _, err := filepath.Match(f, ""); err != nil {
+			errs.AddMsg(field, "must be a valid glob pattern")
+		}
+	}
+	return errs.Err()
+}
+
+// MaxLocales is the limit on entries in config.locales.
+const MaxLocales = 16
+
+// ValidateLocales checks limits, formatting, and uniqueness for locales.
+func ValidateLocales(locales []string, fieldName string) error {
+	var errs ErrorList
+	if len(locales) > MaxLocales {
+		errs.AddMsg(fieldName, fmt.Sprintf("cannot have more than %d locales", MaxLocales))
+	}
+
+	seen := make(map[string]bool, len(locales))
+	for i, locale := range locales {
+		field := fmt.Sprintf("%s[%d]", fieldName, i)
+
+		if len(locale) > 32 || !localeRegex.MatchString(locale) {
+			errs.AddMsg(field, "must be a WordPress locale such as de_DE or fr_FR")
+		}
+
+		if seen[locale] {
+			errs.AddMsg(fieldName, fmt.Sprintf("duplicate locale '%s'", locale))
+		}
+		seen[locale] = true
+	}
+	return errs.Err()
+}
+
 // IsValidAuthor checks the author name length and character safety.
 func IsValidAuthor(author string) error {
 	if len(author) < 2 {
@@ -195,3 +195,51 @@ func TestValidateDependencyIntegrity(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateLocales(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		wantErr bool
+	}{
+		{"language only", []string{"de"}, false},
+		{"language and region", []string{"de_DE", "fr_FR", "pt_BR"}, false},
+		{"variant", []string{"de_DE_formal", "pt_PT_ao90"}, false},
+		{"hyphen rejected", []string{"de-DE"}, true},
+		{"lowercase region rejected", []string{"de_de"}, true},
+		{"path rejected", []string{"../de_DE"}, true},
+		{"duplicate rejected", []string{"de_DE", "de_DE"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateLocales(tc.input, "config.locales")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ValidateLocales(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		wantErr bool
+	}{
+		{"paths and globs", []string{"src", "assets/*.css", "includes/**/*.php"}, false},
+		{"absolute rejected", []string{"/src"}, true},
+		{"escape rejected", []string{"../shared"}, true},
+		{"empty rejected", []string{""}, true},
+		{"bad glob rejected", []string{"src/[a-"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFiles(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ValidateFiles(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+		})
+	}
+}
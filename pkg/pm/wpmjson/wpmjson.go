@@ -4,15 +4,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"go.wpm.so/cli/pkg/atomicwriter"
 	"go.wpm.so/cli/pkg/pm"
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
 	"go.wpm.so/cli/pkg/pm/wpmjson/validator"
+	"go.wpm.so/cli/pkg/wp/layout"
 )
 
-const ConfigFile = "wpm.json"
+const (
+	ConfigFile = "wpm.json"
+
+	// DefaultProjectName is used when a directory name has nothing usable
+	// as a package name.
+	DefaultProjectName = "wordpress-site"
+)
+
+var nonNameChars = regexp.MustCompile(`[^a-z0-9]+`)
 
 // Config struct to define the wpm.json schema
 type Config struct {
@@ -27,26 +39,43 @@ type Config struct {
 	Author          string               `json:"author,omitempty"`
 	Homepage        string               `json:"homepage,omitempty"`
 	Tags            []string             `json:"tags,omitempty"`
+	Files           []string             `json:"files,omitempty"`
 	Dependencies    *types.Dependencies  `json:"dependencies,omitempty"`
 	DevDependencies *types.Dependencies  `json:"devDependencies,omitempty"`
 	Config          *types.PackageConfig `json:"config,omitempty"`
 	Scripts         *types.Scripts       `json:"scripts,omitempty"`
 
 	// Internal fields.
-	Indentation          string              `json:"-"`
-	defaultPackageConfig types.PackageConfig `json:"-"`
+	Indentation string         `json:"-"`
+	dir         string         // the project directory, "" if there is none
+	layout      *layout.Layout // detected in dir on first use
 }
 
-var defaultPackageConfig = types.PackageConfig{
-	BinDir:     "wp-bin",
-	ContentDir: "wp-content",
-}
+const defaultBinDir = "wp-bin"
 
 // New returns a new instance of wpm.json config
 func New() *Config {
-	return &Config{
-		defaultPackageConfig: defaultPackageConfig,
+	return &Config{}
+}
+
+// NewInDir returns a new instance of wpm.json config whose directory defaults
+// follow the WordPress layout detected in cwd (Bedrock, or WP_CONTENT_DIR and
+// WP_PLUGIN_DIR in wp-config.php).
+func NewInDir(cwd string) *Config {
+	return &Config{dir: cwd}
+}
+
+// detectedLayout returns the WordPress layout of the project directory. It is
+// only detected once a directory is needed, since that reads wp-config.php.
+func (c *Config) detectedLayout() *layout.Layout {
+	if c.layout == nil {
+		if c.dir == "" {
+			c.layout = layout.Default()
+		} else {
+			c.layout = layout.Detect(c.dir)
+		}
 	}
+	return c.layout
 }
 
 // BinDir returns the bin directory from the config or the default if not set
@@ -54,15 +83,112 @@ func (c *Config) BinDir() string {
 	if c.Config != nil && c.Config.BinDir != "" {
 		return c.Config.BinDir
 	}
-	return c.defaultPackageConfig.BinDir
+	return defaultBinDir
 }
 
-// ContentDir returns the content directory from the config or the default if not set
+// ContentDir returns the content directory from the config, or the detected
+// one if not set
 func (c *Config) ContentDir() string {
 	if c.Config != nil && c.Config.ContentDir != "" {
 		return c.Config.ContentDir
 	}
-	return c.defaultPackageConfig.ContentDir
+	return c.detectedLayout().ContentDir
+}
+
+// PluginDir returns the plugin directory from the config. When unset it is the
+// detected plugin directory, or "plugins" under ContentDir.
+func (c *Config) PluginDir() string {
+	if c.Config != nil && c.Config.PluginDir != "" {
+		return c.Config.PluginDir
+	}
+	if !c.hasContentDir() && c.detectedLayout().PluginDir != "" {
+		return c.detectedLayout().PluginDir
+	}
+	return path.Join(c.ContentDir(), "plugins")
+}
+
+// ThemeDir returns the theme directory from the config, or "themes" under
+// ContentDir if not set.
+func (c *Config) ThemeDir() string {
+	if c.Config != nil && c.Config.ThemeDir != "" {
+		return c.Config.ThemeDir
+	}
+	return path.Join(c.ContentDir(), "themes")
+}
+
+// hasContentDir reports whether config.content-dir is set explicitly, in which
+// case a detected plugin directory no longer applies.
+func (c *Config) hasContentDir() bool {
+	return c.Config != nil && c.Config.ContentDir != ""
+}
+
+// RuntimeWP returns the WordPress runtime version from the config, or an empty string if not set
+func (c *Config) RuntimeWP() string {
+	if c.Config == nil || c.Config.Runtime == nil {
+		return ""
+	}
+	return c.Config.Runtime.WP
+}
+
+// CoreSource returns the core source configuration, or nil if not set
+func (c *Config) CoreSource() *types.Core {
+	if c.Config == nil {
+		return nil
+	}
+	return c.Config.Core
+}
+
+// Locales returns the translation locales from the config, or nil if not set
+func (c *Config) Locales() []string {
+	if c.Config == nil {
+		return nil
+	}
+	return c.Config.Locales
+}
+
+// TranslationsSource returns the translations source configuration, or nil if not set
+func (c *Config) TranslationsSource() *types.Translations {
+	if c.Config == nil {
+		return nil
+	}
+	return c.Config.Translations
+}
+
+// TrustedAuthors returns the author keys packages must be signed with, or nil
+// if author signatures aren't required
+func (c *Config) TrustedAuthors() []types.AuthorKey {
+	if c.Config == nil {
+		return nil
+	}
+	return c.Config.TrustedAuthors
+}
+
+// LicensePolicy returns the license policy from the config, or nil if not set
+func (c *Config) LicensePolicy() *types.Licenses {
+	if c.Config == nil || c.Config.Licenses == nil {
+		return nil
+	}
+	if len(c.Config.Licenses.Allow) == 0 && len(c.Config.Licenses.Deny) == 0 {
+		return nil
+	}
+	return c.Config.Licenses
+}
+
+// InstallPolicy returns the install policy from the config, or nil if not set
+func (c *Config) InstallPolicy() *types.Policy {
+	if c.Config == nil {
+		return nil
+	}
+	return c.Config.Policy
+}
+
+// MinimumReleaseAge returns the minimum release age from the config, and the
+// packages exempt from it. The age is "" if not set.
+func (c *Config) MinimumReleaseAge() (string, []string) {
+	if c.Config == nil {
+		return "", nil
+	}
+	return c.Config.MinimumReleaseAge, c.Config.MinimumReleaseAgeExclude
 }
 
 // RuntimeStrict returns the runtime strict mode from the config or the default if not set
@@ -98,6 +224,9 @@ func (c *Config) Validate() error {
 	if len(c.Tags) > 0 {
 		errs.MustMerge(validator.ValidateTags(c.Tags))
 	}
+	if len(c.Files) > 0 {
+		errs.MustMerge(validator.ValidateFiles(c.Files))
+	}
 
 	// Core fields
 	if c.Requires != nil {
@@ -124,6 +253,31 @@ func (c *Config) Validate() error {
 		if c.Config.ContentDir != "" {
 			errs.Add("config.content-dir", validator.IsValidProjectRelPath(c.Config.ContentDir))
 		}
+
+		if c.Config.PluginDir != "" {
+			errs.Add("config.plugin-dir", validator.IsValidProjectRelPath(c.Config.PluginDir))
+		}
+
+		if c.Config.ThemeDir != "" {
+			errs.Add("config.theme-dir", validator.IsValidProjectRelPath(c.Config.ThemeDir))
+		}
+
+		if c.Config.Core != nil {
+			if c.Config.Core.Package != "" {
+				errs.Add("config.core.package", validator.IsValidPackageName(c.Config.Core.Package))
+			}
+			if c.Config.Core.URL != "" {
+				errs.Add("config.core.url", validator.IsValidSourceURL(c.Config.Core.URL))
+			}
+		}
+
+		if len(c.Config.Locales) > 0 {
+			errs.MustMerge(validator.ValidateLocales(c.Config.Locales, "config.locales"))
+		}
+
+		if c.Config.Translations != nil && c.Config.Translations.URL != "" {
+			errs.Add("config.translations.url", validator.IsValidSourceURL(c.Config.Translations.URL))
+		}
 	}
 
 	return errs.Err()
@@ -154,6 +308,16 @@ func (c *Config) GetIndentation() string {
 	return "  " // Default to 2 spaces if not set
 }
 
+// ProjectName turns a directory name into a valid package name for a
+// project config, such as "example-com" for "Example.com".
+func ProjectName(dir string) string {
+	name := strings.Trim(nonNameChars.ReplaceAllString(strings.ToLower(dir), "-"), "-")
+	if validator.IsValidPackageName(name) != nil {
+		return DefaultProjectName
+	}
+	return name
+}
+
 // Read loads the wpm.json file from the specified directory.
 func Read(cwd string) (*Config, error) {
 	path := filepath.Join(cwd, ConfigFile)
@@ -166,13 +330,24 @@ func Read(cwd string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read wpm.json: %w", err)
 	}
 
+	config, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	config.dir = cwd
+
+	return config, nil
+}
+
+// Parse decodes the contents of a wpm.json file, such as one read from a
+// package tarball.
+func Parse(data []byte) (*Config, error) {
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse wpm.json: %w", err)
 	}
 
 	config.Indentation = pm.DetectIndentation(data)
-	config.defaultPackageConfig = defaultPackageConfig
 
 	return &config, nil
 }
@@ -1,10 +1,13 @@
 package wpmjson
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"go.wpm.so/cli/pkg/pm/wpmjson/types"
+	"go.wpm.so/cli/pkg/wp/layout"
 )
 
 func baseConfig() *Config {
@@ -76,3 +79,74 @@ func TestConfigValidateDependencyIntegrity(t *testing.T) {
 		}
 	})
 }
+
+func TestConfigPackageDirs(t *testing.T) {
+	detected := layout.Layout{Kind: layout.KindWpConfig, ContentDir: "web/app", PluginDir: "web/plugins"}
+
+	tests := []struct {
+		name        string
+		config      *types.PackageConfig
+		wantContent string
+		wantPlugins string
+		wantThemes  string
+	}{
+		{"detected defaults", nil, "web/app", "web/plugins", "web/app/themes"},
+		{"explicit content dir overrides detection", &types.PackageConfig{ContentDir: "content"}, "content", "content/plugins", "content/themes"},
+		{"explicit plugin and theme dirs", &types.PackageConfig{PluginDir: "p", ThemeDir: "t"}, "web/app", "p", "t"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := New()
+			cfg.layout = &detected
+			cfg.Config = tc.config
+
+			if got := cfg.ContentDir(); got != tc.wantContent {
+				t.Errorf("ContentDir() = %q, want %q", got, tc.wantContent)
+			}
+			if got := cfg.PluginDir(); got != tc.wantPlugins {
+				t.Errorf("PluginDir() = %q, want %q", got, tc.wantPlugins)
+			}
+			if got := cfg.ThemeDir(); got != tc.wantThemes {
+				t.Errorf("ThemeDir() = %q, want %q", got, tc.wantThemes)
+			}
+		})
+	}
+}
+
+func TestReadDetectsLayoutLazily(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		ConfigFile:      `{"name": "my-site", "private": true}`,
+		"wp-config.php": "<?php\ndefine( 'WP_CONTENT_DIR', __DIR__ . '/content' );\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.layout != nil {
+		t.Error("Read() detected the layout before a directory was needed")
+	}
+	if got := cfg.ContentDir(); got != "content" {
+		t.Errorf("ContentDir() = %q, want %q", got, "content")
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	tests := map[string]string{
+		"My Site":     "my-site",
+		"example.com": "example-com",
+		"__":          DefaultProjectName,
+	}
+	for in, want := range tests {
+		if got := ProjectName(in); got != want {
+			t.Errorf("ProjectName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -21,20 +21,42 @@ const (
 
 // LockPackage represents a specific version of a package locked in the lockfile.
 type LockPackage struct {
-	Version      string               `json:"version"`
-	Signatures   []manifest.Signature `json:"signatures"`
-	Digest       string               `json:"digest"`
-	Type         types.PackageType    `json:"type"`
-	Bin          *types.Bin           `json:"bin,omitempty"`
-	Dependencies *types.Dependencies  `json:"dependencies,omitempty"`
+	Version         string               `json:"version"`
+	Signatures      []manifest.Signature `json:"signatures"`
+	AuthorSignature *manifest.Signature  `json:"authorSignature,omitempty"`
+	Digest          string               `json:"digest"`
+	Type            types.PackageType    `json:"type"`
+	License         string               `json:"license,omitempty"`
+	Bin             *types.Bin           `json:"bin,omitempty"`
+	Dependencies    *types.Dependencies  `json:"dependencies,omitempty"`
+}
+
+// LockCore represents the WordPress core release installed in the project root.
+type LockCore struct {
+	Version    string               `json:"version"`
+	Source     string               `json:"source"`
+	Digest     string               `json:"digest"`
+	Signatures []manifest.Signature `json:"signatures,omitempty"`
+}
+
+// LockTranslation represents a translation pack installed for a locked package.
+// Lockfile.Translations is keyed by package name, then locale.
+// Files lists the pack's files in the languages directory so they can be removed later.
+type LockTranslation struct {
+	Version string   `json:"version"`
+	Source  string   `json:"source"`
+	Digest  string   `json:"digest"`
+	Files   []string `json:"files"`
 }
 
 // Lockfile represents the state of the dependency tree.
 // Since wpm does not support nesting, this is a flat map of package names to their locked details.
 type Lockfile struct {
-	LockfileVersion int                    `json:"lockfileVersion"`
-	Packages        map[string]LockPackage `json:"packages"`
-	Indentation     string                 `json:"-"`
+	LockfileVersion int                                   `json:"lockfileVersion"`
+	Core            *LockCore                             `json:"core,omitempty"`
+	Packages        map[string]LockPackage                `json:"packages"`
+	Translations    map[string]map[string]LockTranslation `json:"translations,omitempty"`
+	Indentation     string                                `json:"-"`
 }
 
 // New creates a new empty Lockfile instance with the current version.
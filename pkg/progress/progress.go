@@ -7,6 +7,7 @@ import (
 
 	"github.com/briandowns/spinner"
 
+	"go.wpm.so/cli/pkg/output"
 	"go.wpm.so/cli/pkg/unsafeconv"
 )
 
@@ -73,6 +74,8 @@ func (p *Progress) RunWithProgress(label string, run func() error, out io.Writer
 	return run()
 }
 
+// Stream replaces the progress indicator with a line of text. The text often
+// names packages from the registry, so it is sanitized.
 func (p *Progress) Stream(out io.Writer, text string) {
 	if !p.ProgressIndicatorEnabled {
 		return
@@ -85,5 +88,5 @@ func (p *Progress) Stream(out io.Writer, text string) {
 		p.progressIndicator.Stop()
 	}
 
-	_, _ = out.Write(unsafeconv.UnsafeStringToBytes("\r" + text + "\033[K"))
+	_, _ = out.Write(unsafeconv.UnsafeStringToBytes("\r" + output.Sanitize(text) + "\033[K"))
 }
@@ -0,0 +1,201 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var (
+	composerOrSplit   = regexp.MustCompile(`\s*\|\|?\s*`)
+	composerAndSplit  = regexp.MustCompile(`\s*,\s*|\s+`)
+	composerOperator  = regexp.MustCompile(`^(>=|<=|!=|==|<>|>|<|=|\^|~)?\s*(.+)$`)
+	composerOpSpace   = regexp.MustCompile(`(>=|<=|!=|==|<>|>|<|=|\^|~)\s+`)
+	composerStability = regexp.MustCompile(`@(dev|alpha|beta|rc|RC|stable)$`)
+)
+
+// ComposerConstraint translates a Composer version constraint into the
+// semver constraint syntax used by wpm.
+//
+// Composer and semver agree on most operators. The differences handled here:
+//   - "|" is an alias for "||"
+//   - "~1.2" allows 1.x from 1.2 up, where semver's "~1.2" stops at 1.3
+//   - "@stability" flags are dropped
+//   - whitespace after an operator is allowed, as in ">= 1.0"
+//   - versions are normalized, so "v1.2" and "1.0.0.0" are accepted
+//
+// Branch constraints such as "dev-main" have no semver equivalent and
+// return an error.
+func ComposerConstraint(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return "", errors.New("constraint cannot be empty")
+	}
+
+	groups := composerOrSplit.Split(constraint, -1)
+	out := make([]string, 0, len(groups))
+	for _, group := range groups {
+		translated, err := composerAndGroup(group)
+		if err != nil {
+			return "", err
+		}
+		out = append(out, translated)
+	}
+
+	result := strings.Join(out, " || ")
+	if _, err := semver.NewConstraint(result); err != nil {
+		return "", fmt.Errorf("cannot translate %q: %w", constraint, err)
+	}
+	return result, nil
+}
+
+// ComposerExact returns the version a Composer constraint pins exactly, such
+// as "1.2" or "=v1.2.0", and false for anything that allows a range.
+func ComposerExact(constraint string) (string, bool) {
+	c := composerStability.ReplaceAllString(strings.TrimSpace(constraint), "")
+	c = strings.TrimSpace(strings.TrimLeft(c, "="))
+	if c == "" || strings.ContainsAny(c, "<>!^~*|, ") || strings.HasSuffix(strings.ToLower(c), ".x") {
+		return "", false
+	}
+	v, err := Normalize(c)
+	if err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+func composerAndGroup(group string) (string, error) {
+	// Join operators to their versions first, so that ">= 1.0" stays one
+	// term rather than splitting like an AND.
+	group = composerOpSpace.ReplaceAllString(strings.TrimSpace(group), "$1")
+	tokens := composerAndSplit.Split(group, -1)
+
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		// Hyphen ranges ("1.0 - 2.0") arrive as three tokens.
+		if i+2 < len(tokens) && tokens[i+1] == "-" {
+			lo, err := composerVersion(tokens[i])
+			if err != nil {
+				return "", err
+			}
+			hi, err := composerUpperBound(tokens[i+2])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, ">="+lo+", "+hi)
+			i += 2
+			continue
+		}
+
+		part, err := composerTerm(tokens[i])
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "*", nil
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+func composerTerm(term string) (string, error) {
+	term = composerStability.ReplaceAllString(term, "")
+	if term == "" || strings.HasPrefix(term, "@") {
+		// A bare stability flag such as "@dev" constrains nothing.
+		return "", nil
+	}
+	if strings.HasPrefix(term, "dev-") || strings.HasSuffix(term, "-dev") {
+		return "", fmt.Errorf("branch constraint %q is not supported", term)
+	}
+	if term == "*" {
+		return "*", nil
+	}
+
+	m := composerOperator.FindStringSubmatch(term)
+	if m == nil {
+		return "", fmt.Errorf("invalid constraint %q", term)
+	}
+	op, raw := m[1], m[2]
+
+	if isWildcard(raw) {
+		if op != "" && op != "=" && op != "==" {
+			return "", fmt.Errorf("invalid constraint %q", term)
+		}
+		return strings.ReplaceAll(strings.TrimLeft(raw, "vV"), "x", "*"), nil
+	}
+
+	v, err := composerVersion(raw)
+	if err != nil {
+		return "", err
+	}
+
+	switch op {
+	case "~":
+		return composerTilde(raw, v)
+	case "==":
+		return "=" + v, nil
+	case "<>":
+		return "!=" + v, nil
+	default:
+		return op + v, nil
+	}
+}
+
+// composerTilde expands "~X" and "~X.Y" to the next major version. With three
+// or more segments Composer and semver agree.
+func composerTilde(raw, normalized string) (string, error) {
+	if segments := strings.Count(strings.TrimLeft(raw, "vV"), ".") + 1; segments >= 3 {
+		return "~" + normalized, nil
+	}
+	sv, err := semver.StrictNewVersion(normalized)
+	if err != nil {
+		return "", err
+	}
+	next := strconv.FormatUint(sv.Major()+1, 10) + ".0.0"
+	return ">=" + normalized + ", <" + next, nil
+}
+
+// composerUpperBound returns the upper end of a hyphen range. A partial
+// version is a wildcard, so "- 2.1" allows any 2.1.x.
+func composerUpperBound(raw string) (string, error) {
+	v, err := composerVersion(raw)
+	if err != nil {
+		return "", err
+	}
+	sv, err := semver.StrictNewVersion(v)
+	if err != nil {
+		return "", err
+	}
+	switch strings.Count(strings.TrimLeft(raw, "vV"), ".") {
+	case 0:
+		return "<" + strconv.FormatUint(sv.Major()+1, 10) + ".0.0", nil
+	case 1:
+		return "<" + strconv.FormatUint(sv.Major(), 10) + "." + strconv.FormatUint(sv.Minor()+1, 10) + ".0", nil
+	default:
+		return "<=" + v, nil
+	}
+}
+
+func composerVersion(raw string) (string, error) {
+	v, err := Normalize(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %q in constraint", raw)
+	}
+	return v, nil
+}
+
+func isWildcard(v string) bool {
+	for _, seg := range strings.Split(v, ".") {
+		if seg == "*" || seg == "x" || seg == "X" {
+			return true
+		}
+	}
+	return false
+}
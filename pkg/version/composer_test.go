@@ -0,0 +1,65 @@
+package version
+
+import "testing"
+
+func TestComposerConstraint(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"1.2.3", "1.2.3", false},
+		{"v1.2", "1.2.0", false},
+		{"^1.2", "^1.2.0", false},
+		{"~1.2", ">=1.2.0, <2.0.0", false},
+		{"~0.3", ">=0.3.0, <1.0.0", false},
+		{"~1.2.3", "~1.2.3", false},
+		{">=1.0 <2.0", ">=1.0.0, <2.0.0", false},
+		{">=1.0,<2.0", ">=1.0.0, <2.0.0", false},
+		{"^1.0 | ^2.0", "^1.0.0 || ^2.0.0", false},
+		{"^1.0 || ^2.0", "^1.0.0 || ^2.0.0", false},
+		{"1.0 - 2.1", ">=1.0.0, <2.2.0", false},
+		{"1.0.0 - 2.1.3", ">=1.0.0, <=2.1.3", false},
+		{"1.2.*", "1.2.*", false},
+		{"*", "*", false},
+		{"^5.0@beta", "^5.0.0", false},
+		{"<>1.0", "!=1.0.0", false},
+		{"==1.0", "=1.0.0", false},
+		{">= 1.0", ">=1.0.0", false},
+		{">= 1.0 < 2.0", ">=1.0.0, <2.0.0", false},
+		{">= 1.0, <= 2.0", ">=1.0.0, <=2.0.0", false},
+		{"^ 1.2 || ~ 2.0", "^1.2.0 || >=2.0.0, <3.0.0", false},
+		{"dev-main", "", true},
+		{"2.x-dev", "", true},
+		{"", "", true},
+	}
+
+	for _, tc := range cases {
+		got, err := ComposerConstraint(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ComposerConstraint(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ComposerConstraint(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestComposerExact(t *testing.T) {
+	cases := map[string]string{
+		"1.2":      "1.2.0",
+		"=v1.2.3":  "1.2.3",
+		"5.3@beta": "5.3.0",
+		"^1.2":     "",
+		"1.2.*":    "",
+		"1.x":      "",
+		">=1.0":    "",
+	}
+	for in, want := range cases {
+		got, ok := ComposerExact(in)
+		if ok != (want != "") || got != want {
+			t.Errorf("ComposerExact(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
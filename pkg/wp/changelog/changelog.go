@@ -0,0 +1,80 @@
+// Package changelog extracts release notes from WordPress readmes.
+package changelog
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+
+	"go.wpm.so/cli/pkg/version"
+	"go.wpm.so/cli/pkg/wp/parser"
+)
+
+// Release is the changelog entry of one release, with its upgrade notice
+// when the readme has one.
+type Release struct {
+	Version       string `json:"version"`
+	Notes         string `json:"notes"`
+	UpgradeNotice string `json:"upgradeNotice,omitempty"`
+}
+
+// Between returns the releases in readme newer than from and no newer than
+// to, newest first. Either bound may be empty to leave that side open.
+func Between(readme, from, to string) ([]Release, error) {
+	lower, err := bound(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := bound(to)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.NewReadmeParser()
+	p.Parse(readme)
+
+	notices := make(map[string]string, len(p.UpgradeNotice))
+	for title, notice := range p.UpgradeNotice {
+		if v, err := version.Normalize(title); err == nil {
+			notices[v] = notice
+		}
+	}
+
+	var releases []Release
+	seen := make(map[string]bool)
+	for _, entry := range p.Changelog() {
+		v, err := semver.StrictNewVersion(entry.Version)
+		if err != nil || seen[entry.Version] {
+			continue
+		}
+		if lower != nil && !v.GreaterThan(lower) {
+			continue
+		}
+		if upper != nil && v.GreaterThan(upper) {
+			continue
+		}
+		seen[entry.Version] = true
+		releases = append(releases, Release{
+			Version:       entry.Version,
+			Notes:         entry.Notes,
+			UpgradeNotice: notices[entry.Version],
+		})
+	}
+
+	slices.SortStableFunc(releases, func(a, b Release) int {
+		return semver.MustParse(b.Version).Compare(semver.MustParse(a.Version))
+	})
+	return releases, nil
+}
+
+func bound(v string) (*semver.Version, error) {
+	if v == "" {
+		return nil, nil
+	}
+	normalized, err := version.Normalize(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", v, err)
+	}
+	return semver.StrictNewVersion(normalized)
+}
@@ -0,0 +1,67 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+const readme = `=== Akismet ===
+Stable tag: 5.4
+
+== Changelog ==
+
+= 5.4 =
+* Add a setting.
+
+= 5.3.2 =
+* Fix a notice.
+
+= 5.3.1 =
+* Fix a warning.
+
+= 5.3 =
+* Initial.
+
+== Upgrade Notice ==
+
+= 5.4 =
+Requires PHP 7.4.
+`
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{name: "range", from: "5.3.1", to: "5.4", want: []string{"5.4.0", "5.3.2"}},
+		{name: "open end", from: "5.3.2", want: []string{"5.4.0"}},
+		{name: "open start", to: "5.3.1", want: []string{"5.3.1", "5.3.0"}},
+		{name: "empty", from: "5.4.0", to: "5.4.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releases, err := Between(readme, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range releases {
+				got = append(got, r.Version)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Between(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	releases, _ := Between(readme, "5.3.2", "")
+	if len(releases) != 1 || releases[0].UpgradeNotice != "Requires PHP 7.4." || releases[0].Notes != "* Add a setting." {
+		t.Errorf("Between() = %+v, want 5.4.0 with its upgrade notice", releases)
+	}
+
+	if _, err := Between(readme, "trunk", ""); err == nil {
+		t.Error("Between() with an invalid bound: want error")
+	}
+}
@@ -0,0 +1,175 @@
+// Package layout detects where a WordPress project keeps its content,
+// plugins and themes.
+package layout
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const maxConfigFileBytes = 256 * 1024
+
+// Kind names how a Layout was detected.
+type Kind string
+
+const (
+	KindDefault  Kind = "default"
+	KindWpConfig Kind = "wp-config.php"
+	KindBedrock  Kind = "bedrock"
+)
+
+var bedrockContentDirRe = regexp.MustCompile(`Config::define\(\s*['"]CONTENT_DIR['"]\s*,\s*['"]([^'"]+)['"]\s*\)`)
+
+// Layout holds project-relative, slash-separated directories. An empty
+// CoreDir means core is installed in the project root, and an empty
+// PluginDir or MuPluginDir means the WordPress default under ContentDir.
+// Themes are always under ContentDir, since WordPress has no constant to move
+// them.
+type Layout struct {
+	Kind        Kind
+	CoreDir     string
+	ContentDir  string
+	PluginDir   string
+	MuPluginDir string
+}
+
+// Default returns the stock WordPress layout.
+func Default() *Layout {
+	return &Layout{Kind: KindDefault, ContentDir: "wp-content"}
+}
+
+// Core returns the directory WordPress core is installed in, "." for the
+// project root.
+func (l *Layout) Core() string {
+	if l.CoreDir != "" {
+		return l.CoreDir
+	}
+	return "."
+}
+
+// Plugins returns the plugin directory, falling back to ContentDir/plugins.
+func (l *Layout) Plugins() string {
+	if l.PluginDir != "" {
+		return l.PluginDir
+	}
+	return path.Join(l.ContentDir, "plugins")
+}
+
+// MuPlugins returns the must-use plugin directory, falling back to
+// ContentDir/mu-plugins.
+func (l *Layout) MuPlugins() string {
+	if l.MuPluginDir != "" {
+		return l.MuPluginDir
+	}
+	return path.Join(l.ContentDir, "mu-plugins")
+}
+
+// Themes returns the theme directory, ContentDir/themes.
+func (l *Layout) Themes() string {
+	return path.Join(l.ContentDir, "themes")
+}
+
+// Detect inspects root for a Bedrock project or a wp-config.php that moves
+// the content or plugin directories, and returns the default layout when
+// neither is found. Directories outside root are ignored, since wpm can only
+// manage what lives inside the project.
+func Detect(root string) *Layout {
+	if l := detectBedrock(root); l != nil {
+		return l
+	}
+	if l := detectWpConfig(root); l != nil {
+		return l
+	}
+	return Default()
+}
+
+// detectBedrock recognizes roots/bedrock: config/application.php next to a
+// web/ document root, with core in web/wp and content under web/app unless
+// CONTENT_DIR says otherwise.
+func detectBedrock(root string) *Layout {
+	appConfig := filepath.Join(root, "config", "application.php")
+	if !isFile(appConfig) || !isFile(filepath.Join(root, "web", "wp-config.php")) {
+		return nil
+	}
+
+	contentDir := "/app"
+	if src, err := readLimited(appConfig); err == nil {
+		if m := bedrockContentDirRe.FindStringSubmatch(src); m != nil {
+			contentDir = m[1]
+		}
+	}
+
+	rel := path.Clean(path.Join("web", contentDir))
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return nil
+	}
+
+	return &Layout{Kind: KindBedrock, CoreDir: "web/wp", ContentDir: rel}
+}
+
+func detectWpConfig(root string) *Layout {
+	configPath := filepath.Join(root, "wp-config.php")
+	src, err := readLimited(configPath)
+	if err != nil {
+		return nil
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	defines := parseDefines(src, filepath.Join(absRoot, "wp-config.php"))
+
+	l := Default()
+	found := false
+	set := func(name string, dst *string) {
+		if v, ok := defines[name]; ok {
+			if rel, ok := relTo(absRoot, v); ok {
+				*dst = rel
+				found = true
+			}
+		}
+	}
+	set("WP_CONTENT_DIR", &l.ContentDir)
+	set("WP_PLUGIN_DIR", &l.PluginDir)
+	set("WPMU_PLUGIN_DIR", &l.MuPluginDir)
+
+	if !found {
+		return nil
+	}
+	l.Kind = KindWpConfig
+	return l
+}
+
+// relTo returns target relative to root in slash form, or false if target
+// is root itself or lies outside it.
+func relTo(root, target string) (string, bool) {
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func readLimited(p string) (string, error) {
+	f, err := os.Open(p) //nolint:gosec // p is project root + a constant
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxConfigFileBytes))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToValidUTF8(string(data), ""), nil
+}
+
+func isFile(p string) bool {
+	fi, err := os.Stat(p)
+	return err == nil && fi.Mode().IsRegular()
+}
@@ -0,0 +1,114 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func write(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  Layout
+	}{
+		{
+			name:  "empty project",
+			files: nil,
+			want:  Layout{Kind: KindDefault, ContentDir: "wp-content"},
+		},
+		{
+			name: "wp-config without overrides",
+			files: map[string]string{
+				"wp-config.php": "<?php\ndefine( 'DB_NAME', 'wp' );\n",
+			},
+			want: Layout{Kind: KindDefault, ContentDir: "wp-content"},
+		},
+		{
+			name: "wp-config with __DIR__ content dir",
+			files: map[string]string{
+				"wp-config.php": "<?php\ndefine( 'WP_CONTENT_DIR', __DIR__ . '/content' );\n",
+			},
+			want: Layout{Kind: KindWpConfig, ContentDir: "content"},
+		},
+		{
+			name: "wp-config with dirname and derived plugin dirs",
+			files: map[string]string{
+				"wp-config.php": `<?php
+// define( 'WP_CONTENT_DIR', '/ignored' );
+/* define( 'WP_PLUGIN_DIR', '/ignored' ); */
+define('WP_CONTENT_DIR', dirname(__FILE__) . "/app");
+define('WP_PLUGIN_DIR', WP_CONTENT_DIR . '/extensions');
+define('WPMU_PLUGIN_DIR', ABSPATH . 'app/must-use');
+`,
+			},
+			want: Layout{Kind: KindWpConfig, ContentDir: "app", PluginDir: "app/extensions", MuPluginDir: "app/must-use"},
+		},
+		{
+			name: "content dir outside project is ignored",
+			files: map[string]string{
+				"wp-config.php": "<?php\ndefine( 'WP_CONTENT_DIR', dirname( __DIR__, 2 ) . '/shared' );\n",
+			},
+			want: Layout{Kind: KindDefault, ContentDir: "wp-content"},
+		},
+		{
+			name: "unsupported expression is ignored",
+			files: map[string]string{
+				"wp-config.php": "<?php\ndefine( 'WP_CONTENT_DIR', getenv('CONTENT') );\n",
+			},
+			want: Layout{Kind: KindDefault, ContentDir: "wp-content"},
+		},
+		{
+			name: "bedrock",
+			files: map[string]string{
+				"config/application.php": "<?php\nConfig::define('CONTENT_DIR', '/app');\n",
+				"web/wp-config.php":      "<?php\nrequire_once dirname(__DIR__) . '/vendor/autoload.php';\n",
+			},
+			want: Layout{Kind: KindBedrock, CoreDir: "web/wp", ContentDir: "web/app"},
+		},
+		{
+			name: "bedrock with custom content dir",
+			files: map[string]string{
+				"config/application.php": "<?php\nConfig::define( 'CONTENT_DIR', '/content' );\n",
+				"web/wp-config.php":      "<?php\n",
+			},
+			want: Layout{Kind: KindBedrock, CoreDir: "web/wp", ContentDir: "web/content"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tc.files {
+				write(t, root, name, content)
+			}
+			if got := Detect(root); *got != tc.want {
+				t.Fatalf("Detect() = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLayoutDirs(t *testing.T) {
+	l := Layout{ContentDir: "web/app", PluginDir: "web/plugins"}
+	if got := l.Plugins(); got != "web/plugins" {
+		t.Errorf("Plugins() = %q", got)
+	}
+	if got := l.Themes(); got != "web/app/themes" {
+		t.Errorf("Themes() = %q", got)
+	}
+	if got := l.MuPlugins(); got != "web/app/mu-plugins" {
+		t.Errorf("MuPlugins() = %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+package layout
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	defineRe       = regexp.MustCompile(`(?s)\bdefine\s*\(\s*['"]([A-Z_]+)['"]\s*,\s*(.+?)\s*\)\s*;`)
+	blockCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	dirnameRe      = regexp.MustCompile(`(?s)^dirname\s*\((.*)\)$`)
+)
+
+var errUnsupportedExpr = errors.New("unsupported expression")
+
+// parseDefines statically evaluates the path constants defined in a
+// wp-config.php file located at configPath. Only string literals, __FILE__,
+// __DIR__, dirname(), ABSPATH, WP_CONTENT_DIR and the "." operator are
+// understood; defines using anything else are skipped. Results are cleaned
+// absolute paths.
+func parseDefines(src, configPath string) map[string]string {
+	src = stripComments(src)
+
+	env := map[string]string{
+		"__FILE__": configPath,
+		"__DIR__":  filepath.Dir(configPath),
+		// Until wp-config.php says otherwise, WordPress lives next to it.
+		"ABSPATH": filepath.Dir(configPath) + string(filepath.Separator),
+	}
+
+	defines := make(map[string]string)
+	for _, m := range defineRe.FindAllStringSubmatch(src, -1) {
+		name, expr := m[1], m[2]
+		switch name {
+		case "ABSPATH", "WP_CONTENT_DIR", "WP_PLUGIN_DIR", "WPMU_PLUGIN_DIR":
+		default:
+			continue
+		}
+
+		v, err := evalExpr(expr, env)
+		if err != nil || !filepath.IsAbs(v) {
+			continue
+		}
+
+		env[name] = v
+		if name != "ABSPATH" {
+			defines[name] = filepath.Clean(v)
+		}
+	}
+	return defines
+}
+
+// stripComments removes block comments and whole-line // and # comments.
+// Trailing comments are left alone so URLs in string literals survive.
+func stripComments(src string) string {
+	src = blockCommentRe.ReplaceAllString(src, "")
+
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func evalExpr(expr string, env map[string]string) (string, error) {
+	terms, err := splitTopLevel(expr, '.')
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, term := range terms {
+		v, err := evalTerm(term, env)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+	}
+	return filepath.FromSlash(b.String()), nil
+}
+
+func evalTerm(term string, env map[string]string) (string, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return "", errUnsupportedExpr
+	}
+
+	if q := term[0]; q == '\'' || q == '"' {
+		if len(term) < 2 || term[len(term)-1] != q {
+			return "", errUnsupportedExpr
+		}
+		s := term[1 : len(term)-1]
+		if strings.ContainsAny(s, `\`+string(q)) || (q == '"' && strings.Contains(s, "$")) {
+			return "", errUnsupportedExpr
+		}
+		return s, nil
+	}
+
+	if v, ok := env[term]; ok {
+		return v, nil
+	}
+
+	if m := dirnameRe.FindStringSubmatch(term); m != nil {
+		args, err := splitTopLevel(m[1], ',')
+		if err != nil || len(args) == 0 || len(args) > 2 {
+			return "", errUnsupportedExpr
+		}
+
+		levels := 1
+		if len(args) == 2 {
+			levels, err = strconv.Atoi(strings.TrimSpace(args[1]))
+			if err != nil || levels < 1 {
+				return "", errUnsupportedExpr
+			}
+		}
+
+		p, err := evalExpr(args[0], env)
+		if err != nil {
+			return "", err
+		}
+		for range levels {
+			p = filepath.Dir(filepath.Clean(p))
+		}
+		return p, nil
+	}
+
+	return "", errUnsupportedExpr
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside quotes or parentheses.
+func splitTopLevel(s string, sep byte) ([]string, error) {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return nil, errUnsupportedExpr
+			}
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if quote != 0 || depth != 0 {
+		return nil, errUnsupportedExpr
+	}
+	return append(parts, s[start:]), nil
+}
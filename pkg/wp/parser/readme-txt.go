@@ -9,6 +9,8 @@ import (
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+
+	"go.wpm.so/cli/pkg/version"
 )
 
 // Constants for known section keys.
@@ -24,7 +26,10 @@ const (
 	sectionUpgradeNotice            = "upgrade_notice"
 )
 
-var screenshotLineRegex = regexp.MustCompile(`^(\d+)\.\s+(.+)$`)
+var (
+	screenshotLineRegex   = regexp.MustCompile(`^(\d+)\.\s+(.+)$`)
+	changelogVersionRegex = regexp.MustCompile(`\d+(?:\.\d+)+(?:-[0-9A-Za-z.]+)?`)
+)
 
 type ReadmeParser struct {
 	Name            string
@@ -232,7 +237,7 @@ func (p *ReadmeParser) parseSections(lines []string, start int) {
 		isClassicSection := strings.HasPrefix(trimmedLine, "==") && strings.HasSuffix(trimmedLine, "==") &&
 			!strings.HasPrefix(trimmedLine, "===") && len(trimmedLine) >= 4
 
-		isMarkdownSection := strings.HasPrefix(trimmedLine, "##")
+		isMarkdownSection := strings.HasPrefix(trimmedLine, "##") && !strings.HasPrefix(trimmedLine, "###")
 
 		if isClassicSection || isMarkdownSection {
 			saveSection()
@@ -286,13 +291,27 @@ func (p *ReadmeParser) processSpecialSections() {
 // Handles Classic "= Item Title =" and Markdown "### Item Title".
 func (*ReadmeParser) parseBlockStyleItems(content string) map[string]string {
 	itemsMap := make(map[string]string)
+	for _, item := range parseBlockItems(content) {
+		itemsMap[item.title] = item.content
+	}
+	return itemsMap
+}
+
+type blockItem struct {
+	title   string
+	content string
+}
+
+// parseBlockItems splits a section into its titled items, in order.
+func parseBlockItems(content string) []blockItem {
+	var items []blockItem
 	lines := strings.Split(content, "\n")
 	var currentItemTitle string
 	var currentItemContent strings.Builder
 
 	saveItem := func() {
 		if currentItemTitle != "" {
-			itemsMap[currentItemTitle] = strings.Trim(currentItemContent.String(), "\n")
+			items = append(items, blockItem{currentItemTitle, strings.Trim(currentItemContent.String(), "\n")})
 			currentItemContent.Reset()
 		}
 	}
@@ -320,7 +339,33 @@ func (*ReadmeParser) parseBlockStyleItems(content string) map[string]string {
 		}
 	}
 	saveItem()
-	return itemsMap
+	return items
+}
+
+// ChangelogEntry is one release listed in the Changelog section.
+type ChangelogEntry struct {
+	Version string // normalized version found in the title
+	Title   string
+	Notes   string
+}
+
+// Changelog returns the releases listed in the Changelog section, in the
+// order they appear. Items whose title holds no version are skipped.
+func (p *ReadmeParser) Changelog() []ChangelogEntry {
+	content, _, ok := p.getSectionContent(sectionChangelog, sectionChangeLog)
+	if !ok {
+		return nil
+	}
+
+	var entries []ChangelogEntry
+	for _, item := range parseBlockItems(content) {
+		v, err := version.Normalize(changelogVersionRegex.FindString(item.title))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, ChangelogEntry{Version: v, Title: item.title, Notes: item.content})
+	}
+	return entries
 }
 
 func (p *ReadmeParser) parseScreenshots(content string) {
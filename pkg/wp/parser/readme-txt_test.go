@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	tests := []struct {
+		name   string
+		readme string
+		want   []ChangelogEntry
+	}{
+		{
+			name: "classic",
+			readme: `=== Akismet ===
+Stable tag: 5.3.1
+
+== Changelog ==
+
+= 5.3.1 - 2024-01-10 =
+* Fix a notice.
+
+= Older releases =
+See changelog.txt.
+
+= 5.3 =
+* Add a setting.
+`,
+			want: []ChangelogEntry{
+				{Version: "5.3.1", Title: "5.3.1 - 2024-01-10", Notes: "* Fix a notice."},
+				{Version: "5.3.0", Title: "5.3", Notes: "* Add a setting."},
+			},
+		},
+		{
+			name: "markdown",
+			readme: `# Akismet
+
+## Changelog
+
+### v2.0.0-beta.1
+- Rewrite.
+
+## Upgrade Notice
+`,
+			want: []ChangelogEntry{
+				{Version: "2.0.0-beta.1", Title: "v2.0.0-beta.1", Notes: "- Rewrite."},
+			},
+		},
+		{
+			name:   "no changelog",
+			readme: "=== Akismet ===\n\n== Description ==\nSpam.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewReadmeParser()
+			p.Parse(tt.readme)
+			if got := p.Changelog(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changelog() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const maxVersionFileBytes = 16 * 1024
+
+var wpVersionRe = regexp.MustCompile(`(?m)^\s*\$wp_version\s*=\s*['"]([^'"]+)['"]\s*;`)
+
+// ErrCoreNotInstalled is returned by GetCoreVersion when the directory holds no
+// WordPress core install.
+var ErrCoreNotInstalled = errors.New("WordPress core is not installed")
+
+// GetCoreVersion reads $wp_version from wp-includes/version.php under root.
+func GetCoreVersion(root string) (string, error) {
+	path := filepath.Join(root, "wp-includes", "version.php")
+
+	f, err := os.Open(path) //nolint:gosec // path is root + a constant
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrCoreNotInstalled
+		}
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxVersionFileBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	matches := wpVersionRe.FindSubmatch(data)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("no $wp_version found in %s", path)
+	}
+
+	return string(matches[1]), nil
+}
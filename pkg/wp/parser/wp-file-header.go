@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -148,6 +150,33 @@ func GetPluginHeaders(filePath string) (PluginFileHeaders, error) {
 	return headers, nil
 }
 
+// FindMainPluginFile returns the path and headers of the main file of the
+// plugin in dir: the first top-level PHP file with a Plugin Name header.
+func FindMainPluginFile(dir string) (string, PluginFileHeaders, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return "", PluginFileHeaders{}, fmt.Errorf("failed to read plugin files: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(strings.ToLower(file.Name()), phpFileExtension) {
+			continue
+		}
+
+		filePath := filepath.Join(dir, file.Name())
+		headers, err := GetPluginHeaders(filePath)
+		if err != nil {
+			continue
+		}
+
+		if headers.Name != "" {
+			return filePath, headers, nil
+		}
+	}
+
+	return "", PluginFileHeaders{}, errors.New("no main plugin file with valid plugin headers found")
+}
+
 // GetThemeHeaders retrieves headers for a WordPress theme stylesheet.
 func GetThemeHeaders(filePath string) (ThemeFileHeaders, error) {
 	rawHeaders := getRawFileHeaders(filePath, cssFileExtension, themeFileHeaders)
@@ -0,0 +1,58 @@
+// Package wpcli reads and writes the JSON printed by
+// `wp plugin list --format=json` and `wp theme list --format=json`.
+package wpcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxListBytes bounds how much of a dump is read.
+const maxListBytes = 16 << 20
+
+// Statuses reported by WP-CLI.
+const (
+	StatusActive        = "active"
+	StatusActiveNetwork = "active-network"
+	StatusInactive      = "inactive"
+	StatusParent        = "parent"
+	StatusMustUse       = "must-use"
+	StatusDropin        = "dropin"
+)
+
+// Item is one entry of a plugin or theme list. WP-CLI prints more fields
+// when asked; only these are kept.
+type Item struct {
+	Name    string `json:"name"`
+	Status  string `json:"status,omitempty"`
+	Update  string `json:"update,omitempty"`
+	Version string `json:"version"`
+}
+
+// Read decodes a plugin or theme list.
+func Read(r io.Reader) ([]Item, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxListBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxListBytes {
+		return nil, fmt.Errorf("list is larger than %d bytes", maxListBytes)
+	}
+
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("not a WP-CLI list in JSON format: %w", err)
+	}
+	return items, nil
+}
+
+// Write encodes items the way WP-CLI does: a single-line JSON array.
+func Write(w io.Writer, items []Item) error {
+	if items == nil {
+		items = []Item{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(items)
+}